@@ -0,0 +1,104 @@
+package determinism
+
+import (
+	"flag"
+	"go/types"
+	"strings"
+)
+
+// Allowlist is a map of whether the key, as a qualified function name or a
+// package path, is allowed to be called when allowlist mode is enabled (true
+// value means allowed, false means disallowed). A package path that ends with
+// "/..." applies to that package and all packages beneath it. The most
+// specific key wins: a qualified function name over an exact package path,
+// and an exact package path over the longest matching "/..." path.
+type Allowlist map[string]bool
+
+// Clone copies the map and returns it.
+func (a Allowlist) Clone() Allowlist {
+	ret := make(Allowlist, len(a))
+	for k, v := range a {
+		ret[k] = v
+	}
+	return ret
+}
+
+// SetAllStrings sets values based on the given string values. The strings are
+// qualified function names or package paths and are assumed as "true"
+// (allowed) unless the string ends with "=false" which is then treated as
+// false in the map.
+func (a Allowlist) SetAllStrings(refs []string) Allowlist {
+	for _, ref := range refs {
+		if strings.HasSuffix(ref, "=false") {
+			a[strings.TrimSuffix(ref, "=false")] = false
+		} else {
+			a[strings.TrimSuffix(ref, "=true")] = true
+		}
+	}
+	return a
+}
+
+// SetAll sets the given values on this map and returns this map.
+func (a Allowlist) SetAll(refs Allowlist) Allowlist {
+	for k, v := range refs {
+		a[k] = v
+	}
+	return a
+}
+
+// AllowsFunc returns true if the function is allowed to be called.
+func (a Allowlist) AllowsFunc(fn *types.Func) bool {
+	if allowed, ok := a[fn.FullName()]; ok {
+		return allowed
+	} else if fn.Pkg() == nil {
+		// Universe-scope functions like error.Error are always allowed
+		return true
+	}
+	return a.AllowsPackage(fn.Pkg().Path())
+}
+
+// AllowsPackage returns true if the package path is allowed.
+func (a Allowlist) AllowsPackage(path string) bool {
+	if allowed, ok := a[path]; ok {
+		return allowed
+	}
+	// Walk up the path looking for the most specific wildcard
+	for {
+		if allowed, ok := a[path+"/..."]; ok {
+			return allowed
+		}
+		lastSlash := strings.LastIndex(path, "/")
+		if lastSlash == -1 {
+			return false
+		}
+		path = path[:lastSlash]
+	}
+}
+
+// IsStandardPackage returns true if the package path appears to be in the Go
+// standard library, which is the case when the first path element has no dot.
+func IsStandardPackage(path string) bool {
+	firstElem := path
+	if slash := strings.Index(path, "/"); slash >= 0 {
+		firstElem = path[:slash]
+	}
+	return !strings.Contains(firstElem, ".")
+}
+
+type allowlistFlag struct{ allowlist *Allowlist }
+
+// NewAllowlistFlag creates a flag.Value implementation for using
+// Allowlist.SetAllStrings as a CLI flag value. Since a nil allowlist means
+// allowlist mode is disabled, this takes a pointer and creates the map on
+// first set.
+func NewAllowlistFlag(allowlist *Allowlist) flag.Value { return allowlistFlag{allowlist} }
+
+func (allowlistFlag) String() string { return "<none>" }
+
+func (a allowlistFlag) Set(flag string) error {
+	if *a.allowlist == nil {
+		*a.allowlist = Allowlist{}
+	}
+	a.allowlist.SetAllStrings(strings.Split(flag, ","))
+	return nil
+}
@@ -0,0 +1,160 @@
+package determinism
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BoundaryPolicy is how calls to functions outside of the module boundary are
+// treated when module boundary mode is enabled.
+type BoundaryPolicy int
+
+const (
+	// BoundaryPolicyRules only considers the function non-deterministic if it
+	// is marked as such in the ident refs.
+	BoundaryPolicyRules BoundaryPolicy = iota
+	// BoundaryPolicyTrust considers the function deterministic.
+	BoundaryPolicyTrust
+	// BoundaryPolicyDistrust considers the function non-deterministic.
+	BoundaryPolicyDistrust
+)
+
+// ParseBoundaryPolicy parses "rules", "trust", or "distrust" into a policy.
+func ParseBoundaryPolicy(str string) (BoundaryPolicy, error) {
+	switch str {
+	case "rules":
+		return BoundaryPolicyRules, nil
+	case "trust":
+		return BoundaryPolicyTrust, nil
+	case "distrust":
+		return BoundaryPolicyDistrust, nil
+	default:
+		return 0, fmt.Errorf("unknown boundary policy %q", str)
+	}
+}
+
+// String returns the policy in the form accepted by ParseBoundaryPolicy.
+func (b BoundaryPolicy) String() string {
+	switch b {
+	case BoundaryPolicyRules:
+		return "rules"
+	case BoundaryPolicyTrust:
+		return "trust"
+	case BoundaryPolicyDistrust:
+		return "distrust"
+	default:
+		return "<unknown-policy>"
+	}
+}
+
+// BoundaryPolicies is a map of module path to the policy for calls to
+// functions in packages of that module. The special key "std" applies to the
+// standard library. Packages not matching any key use BoundaryPolicyRules.
+type BoundaryPolicies map[string]BoundaryPolicy
+
+// Clone copies the map and returns it.
+func (b BoundaryPolicies) Clone() BoundaryPolicies {
+	ret := make(BoundaryPolicies, len(b))
+	for k, v := range b {
+		ret[k] = v
+	}
+	return ret
+}
+
+// PolicyFor returns the policy for the given package path. The longest module
+// path the package path is at or beneath is used, falling back to "std" for
+// standard library packages.
+func (b BoundaryPolicies) PolicyFor(path string) BoundaryPolicy {
+	for modPath := path; ; {
+		if policy, ok := b[modPath]; ok {
+			return policy
+		}
+		lastSlash := strings.LastIndex(modPath, "/")
+		if lastSlash == -1 {
+			break
+		}
+		modPath = modPath[:lastSlash]
+	}
+	if policy, ok := b["std"]; ok && IsStandardPackage(path) {
+		return policy
+	}
+	return BoundaryPolicyRules
+}
+
+// inBoundary returns true if module boundary mode is disabled or the package
+// path is at or beneath one of the boundary module paths.
+func (c *Checker) inBoundary(path string) bool {
+	if len(c.BoundaryModules) == 0 {
+		return true
+	}
+	for _, modPath := range c.BoundaryModules {
+		if path == modPath || strings.HasPrefix(path, modPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type boundaryModulesFlag struct{ modules *[]string }
+
+// NewBoundaryModulesFlag creates a flag.Value implementation for appending
+// comma-delimited module paths as a CLI flag value.
+func NewBoundaryModulesFlag(modules *[]string) flag.Value { return boundaryModulesFlag{modules} }
+
+func (boundaryModulesFlag) String() string { return "<none>" }
+
+func (b boundaryModulesFlag) Set(flag string) error {
+	*b.modules = append(*b.modules, strings.Split(flag, ",")...)
+	return nil
+}
+
+type boundaryMainModuleFlag struct{ modules *[]string }
+
+// NewBoundaryMainModuleFlag creates a boolean flag.Value implementation that
+// appends the MainModulePath to the module paths when set to true.
+func NewBoundaryMainModuleFlag(modules *[]string) flag.Value { return boundaryMainModuleFlag{modules} }
+
+func (boundaryMainModuleFlag) String() string { return "false" }
+
+func (boundaryMainModuleFlag) IsBoolFlag() bool { return true }
+
+func (b boundaryMainModuleFlag) Set(flag string) error {
+	if enabled, err := strconv.ParseBool(flag); err != nil {
+		return fmt.Errorf("boundary flag does not take a module path, use -boundary-module instead: %w", err)
+	} else if !enabled {
+		return nil
+	}
+	mainModule, err := MainModulePath()
+	if err != nil {
+		return fmt.Errorf("unable to default boundary to main module: %w", err)
+	}
+	*b.modules = append(*b.modules, mainModule)
+	return nil
+}
+
+type boundaryPoliciesFlag struct{ policies BoundaryPolicies }
+
+// NewBoundaryPoliciesFlag creates a flag.Value implementation for setting
+// comma-delimited MODULE=POLICY values as a CLI flag value.
+func NewBoundaryPoliciesFlag(policies BoundaryPolicies) flag.Value {
+	return boundaryPoliciesFlag{policies}
+}
+
+func (boundaryPoliciesFlag) String() string { return "<none>" }
+
+func (b boundaryPoliciesFlag) Set(flag string) error {
+	for _, modPolicy := range strings.Split(flag, ",") {
+		eq := strings.LastIndex(modPolicy, "=")
+		if eq == -1 {
+			return fmt.Errorf("boundary policy %q not in MODULE=POLICY form", modPolicy)
+		}
+		policy, err := ParseBoundaryPolicy(modPolicy[eq+1:])
+		if err != nil {
+			return err
+		}
+		b.policies[modPolicy[:eq]] = policy
+	}
+	return nil
+}
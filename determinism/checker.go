@@ -20,6 +20,19 @@ type Config struct {
 	DebugfFunc func(string, ...interface{})
 	// Must be set to true to see advanced debug logs.
 	Debug bool
+	// If non-nil, enables allowlist mode where every call from non-standard
+	// library code to a function not in the allowlist is non-deterministic.
+	// Allowlisted packages outside of MainModule are trusted.
+	Allowlist Allowlist
+	// If empty, uses MainModulePath, or no module if that fails.
+	MainModule string
+	// If non-empty, enables module boundary mode where only functions in
+	// packages at or beneath these module paths are walked. Calls to functions
+	// outside of them are treated according to BoundaryPolicies.
+	BoundaryModules []string
+	// Policies for functions outside of BoundaryModules. Only used if
+	// BoundaryModules is non-empty.
+	BoundaryPolicies BoundaryPolicies
 }
 
 // Checker is a checker that can run analysis passes to check for
@@ -28,6 +41,12 @@ type Checker struct {
 	IdentRefs  IdentRefs
 	DebugfFunc func(string, ...interface{})
 	Debug      bool
+	// Nil if allowlist mode is disabled.
+	Allowlist  Allowlist
+	MainModule string
+	// Empty if module boundary mode is disabled.
+	BoundaryModules  []string
+	BoundaryPolicies BoundaryPolicies
 }
 
 // NewChecker creates a Checker for the given config.
@@ -41,11 +60,29 @@ func NewChecker(config Config) *Checker {
 	if config.DebugfFunc == nil {
 		config.DebugfFunc = log.Printf
 	}
+	// Clone allowlist if present
+	if config.Allowlist != nil {
+		config.Allowlist = config.Allowlist.Clone()
+	}
+	// Default main module
+	if config.MainModule == "" {
+		config.MainModule, _ = MainModulePath()
+	}
+	// Copy boundary modules and policies
+	config.BoundaryModules = append([]string(nil), config.BoundaryModules...)
+	if config.BoundaryPolicies == nil {
+		config.BoundaryPolicies = BoundaryPolicies{}
+	}
+	config.BoundaryPolicies = config.BoundaryPolicies.Clone()
 	// Build checker
 	return &Checker{
-		IdentRefs:  config.DefaultIdentRefs,
-		DebugfFunc: config.DebugfFunc,
-		Debug:      config.Debug,
+		IdentRefs:        config.DefaultIdentRefs,
+		DebugfFunc:       config.DebugfFunc,
+		Debug:            config.Debug,
+		Allowlist:        config.Allowlist,
+		MainModule:       config.MainModule,
+		BoundaryModules:  config.BoundaryModules,
+		BoundaryPolicies: config.BoundaryPolicies,
 	}
 }
 
@@ -53,6 +90,8 @@ func NewChecker(config Config) *Checker {
 type Result struct {
 	// Only includes top-level functions
 	Funcs map[*types.Func]NonDeterminisms
+
+	funcDecls map[*types.Func]*ast.FuncDecl
 }
 
 // Dump returns the result as a set of lines.
@@ -71,9 +110,11 @@ func (r *Result) Dump(includePos bool) (lines []string) {
 }
 
 // NewAnalyzer creates a Go analysis analyzer that can be used in existing
-// tools. There is a -set-decl flag for adding ident refs overrides and a
-// -determinism-debug flag for enabling debug logs. The result is Result and the
-// facts on functions are *NonDeterminisms.
+// tools. There is a -set-decl flag for adding ident refs overrides, an -allow
+// flag for enabling allowlist mode, -boundary, -boundary-module, and -boundary-policy flags for
+// enabling module boundary mode, and a -determinism-debug flag for enabling
+// debug logs. The result is Result and the facts on functions are
+// *NonDeterminisms.
 func (c *Checker) NewAnalyzer() *analysis.Analyzer {
 	a := &analysis.Analyzer{
 		Name:       "determinism",
@@ -85,6 +126,16 @@ func (c *Checker) NewAnalyzer() *analysis.Analyzer {
 	// Set flags
 	a.Flags.Var(NewIdentRefsFlag(c.IdentRefs), "set-decl",
 		"qualified function/var to include/exclude, overriding the default (append '=false' to exclude)")
+	a.Flags.Var(NewAllowlistFlag(&c.Allowlist), "allow",
+		"qualified function or package path (optionally ending in '/...') to allow calls to, enabling allowlist mode "+
+			"(append '=false' to disallow)")
+	a.Flags.Var(NewBoundaryMainModuleFlag(&c.BoundaryModules), "boundary",
+		"walk functions of the main module only, enabling module boundary mode")
+	a.Flags.Var(NewBoundaryModulesFlag(&c.BoundaryModules), "boundary-module",
+		"module path to walk functions of instead of or in addition to the main module, enabling module boundary mode")
+	a.Flags.Var(NewBoundaryPoliciesFlag(c.BoundaryPolicies), "boundary-policy",
+		"module path (or 'std') and policy of 'rules', 'trust', or 'distrust' in MODULE=POLICY form for calls "+
+			"outside of the boundary")
 	a.Flags.BoolVar(&c.Debug, "determism-debug", c.Debug, "show debug output")
 	return a
 }
@@ -99,15 +150,22 @@ func (c *Checker) debugf(f string, v ...interface{}) {
 func (c *Checker) Run(pass *analysis.Pass) (*Result, error) {
 	c.debugf("Checking package %v", pass.Pkg.Path())
 	// Collect all non-determinisms in the package
-	res := &Result{Funcs: map[*types.Func]NonDeterminisms{}}
+	res := &Result{Funcs: map[*types.Func]NonDeterminisms{}, funcDecls: map[*types.Func]*ast.FuncDecl{}}
 	c.findNonDeterminisms(pass, res)
 	return res, nil
 }
 
+// NodeNonDeterminisms returns the non-determinisms in the given node of the
+// package, such as a function literal that is not a top-level function. The
+// result must be from Run for the same pass.
+func (c *Checker) NodeNonDeterminisms(pass *analysis.Pass, res *Result, node ast.Node) NonDeterminisms {
+	return c.nodeNonDeterminisms(pass, nil, node, res.funcDecls, map[*types.Func]bool{}, res.Funcs)
+}
+
 func (c *Checker) findNonDeterminisms(pass *analysis.Pass, res *Result) {
 	// Collect all top-level func decls and their types. Also mark var decls as
 	// non-deterministic if pattern matches.
-	funcDecls := map[*types.Func]*ast.FuncDecl{}
+	funcDecls := res.funcDecls
 	for _, file := range pass.Files {
 		for _, decl := range file.Decls {
 			switch decl := decl.(type) {
@@ -136,6 +194,12 @@ func (c *Checker) findNonDeterminisms(pass *analysis.Pass, res *Result) {
 			}
 		}
 	}
+	// If the package is outside of the module boundary, calls to its functions
+	// are never walked so there is no reason to walk the decls
+	if !c.inBoundary(pass.Pkg.Path()) {
+		c.debugf("Skipping functions of %v because it is outside the module boundary", pass.Pkg.Path())
+		return
+	}
 	// Walk the decls capturing non-deterministic ones
 	parents := map[*types.Func]bool{}
 	for funcType := range funcDecls {
@@ -164,6 +228,15 @@ func (c *Checker) applyNonDeterminisms(
 		// Recursive call is not marked non-deterministic
 		return nil
 	}
+	// Check if outside of the module boundary, which is never cached since it
+	// may be in a different package
+	if fn.Pkg() != nil && !c.inBoundary(fn.Pkg().Path()) {
+		return c.boundaryNonDeterminisms(pass, fn)
+	}
+	// Check if trusted by the allowlist, which is also never cached
+	if c.allowlistTrusts(fn.Pkg()) && !c.IdentRefs[fn.FullName()] {
+		return nil
+	}
 	// Check if determinisms already set or it's in a different package (which
 	// means we can't re-set later)
 	reasons, alreadySet := results[fn]
@@ -183,76 +256,128 @@ func (c *Checker) applyNonDeterminisms(
 	// If not skipped and has top-level decl, walk the declaration body checking
 	// for non-determinism
 	if !skip && packageDecls[fn] != nil {
-		ast.Inspect(packageDecls[fn], func(n ast.Node) bool {
-			switch n := n.(type) {
-			case *ast.CallExpr:
-				// Check if the call is on a non-deterministic
-				if callee, _ := typeutil.Callee(pass.TypesInfo, n).(*types.Func); callee != nil {
-					// Put self on parents, then remove
-					parents[fn] = true
-					calleeNonDet := c.applyNonDeterminisms(pass, callee, packageDecls, parents, results)
-					delete(parents, fn)
-					// If the callee is non-deterministic, mark this as such
-					if len(calleeNonDet) > 0 {
-						c.debugf("Marking %v as non-determistic because it calls %v", fn.FullName(), callee.FullName())
-						pos := pass.Fset.Position(n.Pos())
-						reasons = append(reasons, &ReasonFuncCall{reasonBase: reasonBase{&pos}, Func: callee, Child: calleeNonDet})
-					}
-				}
-			case *ast.GoStmt:
-				// Any go statement is non-deterministic
-				c.debugf("Marking %v as non-determistic because it starts a goroutine", fn.FullName())
+		reasons = append(reasons, c.nodeNonDeterminisms(pass, fn, packageDecls[fn], packageDecls, parents, results)...)
+	}
+	// Put the reasons fact on the func, even if it is empty
+	results[fn] = reasons
+	return reasons
+}
+
+// Returns the non-determinisms in the given node. The fn is the top-level
+// function the node is in or nil if it is not in one.
+func (c *Checker) nodeNonDeterminisms(
+	pass *analysis.Pass,
+	fn *types.Func,
+	node ast.Node,
+	packageDecls map[*types.Func]*ast.FuncDecl,
+	parents map[*types.Func]bool,
+	results map[*types.Func]NonDeterminisms,
+) (reasons NonDeterminisms) {
+	name := pass.Fset.Position(node.Pos()).String()
+	if fn != nil {
+		name = fn.FullName()
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			// Check if the call is on a non-deterministic
+			callee, _ := typeutil.Callee(pass.TypesInfo, n).(*types.Func)
+			if callee != nil && c.Allowlist != nil && !IsStandardPackage(pass.Pkg.Path()) &&
+				!c.allowlistTrusts(pass.Pkg) && !c.Allowlist.AllowsFunc(callee) {
+				// Calls outside of the allowlist are not walked any further
+				c.debugf("Marking %v as non-determistic because it calls non-allowlisted %v",
+					name, callee.FullName())
 				pos := pass.Fset.Position(n.Pos())
-				reasons = append(reasons, &ReasonConcurrency{reasonBase: reasonBase{&pos}, Kind: ConcurrencyKindGo})
-			case *ast.Ident:
-				// Check if ident is for a non-deterministic var
-				if varType, _ := pass.TypesInfo.ObjectOf(n).(*types.Var); varType != nil {
-					var ignore NonDeterminisms
-					if pass.ImportObjectFact(varType, &ignore) {
-						c.debugf("Marking %v as non-determistic because it accesses %v.%v",
-							fn.FullName(), varType.Pkg().Path(), varType.Name())
-						pos := pass.Fset.Position(n.Pos())
-						reasons = append(reasons, &ReasonVarAccess{reasonBase: reasonBase{&pos}, Var: varType})
-					}
-				}
-			case *ast.RangeStmt:
-				// Map and chan ranges are non-deterministic
-				rangeType := pass.TypesInfo.TypeOf(n.X)
-				// Unwrap named type
-				for {
-					if namedType, _ := rangeType.(*types.Named); namedType != nil {
-						rangeType = namedType.Underlying()
-					} else {
-						break
-					}
+				reasons = append(reasons, &ReasonDisallowedCall{reasonBase: reasonBase{&pos}, Func: callee})
+			} else if callee != nil {
+				// Put self on parents, then remove
+				if fn != nil {
+					parents[fn] = true
 				}
-				switch rangeType.(type) {
-				case *types.Map:
-					c.debugf("Marking %v as non-determistic because it iterates over a map", fn.FullName())
-					pos := pass.Fset.Position(n.Pos())
-					reasons = append(reasons, &ReasonMapRange{reasonBase: reasonBase{&pos}})
-				case *types.Chan:
-					c.debugf("Marking %v as non-determistic because it iterates over a channel", fn.FullName())
+				calleeNonDet := c.applyNonDeterminisms(pass, callee, packageDecls, parents, results)
+				delete(parents, fn)
+				// If the callee is non-deterministic, mark this as such
+				if len(calleeNonDet) > 0 {
+					c.debugf("Marking %v as non-determistic because it calls %v", name, callee.FullName())
 					pos := pass.Fset.Position(n.Pos())
-					reasons = append(reasons, &ReasonConcurrency{reasonBase: reasonBase{&pos}, Kind: ConcurrencyKindRange})
+					reasons = append(reasons, &ReasonFuncCall{reasonBase: reasonBase{&pos}, Func: callee, Child: calleeNonDet})
 				}
-			case *ast.SendStmt:
-				// Any send statement is non-deterministic
-				c.debugf("Marking %v as non-determistic because it sends to a channel", fn.FullName())
-				pos := pass.Fset.Position(n.Pos())
-				reasons = append(reasons, &ReasonConcurrency{reasonBase: reasonBase{&pos}, Kind: ConcurrencyKindSend})
-			case *ast.UnaryExpr:
-				// If the operator is a receive, it is non-deterministic
-				if n.Op == token.ARROW {
-					c.debugf("Marking %v as non-determistic because it receives from a channel", fn.FullName())
+			}
+		case *ast.GoStmt:
+			// Any go statement is non-deterministic
+			c.debugf("Marking %v as non-determistic because it starts a goroutine", name)
+			pos := pass.Fset.Position(n.Pos())
+			reasons = append(reasons, &ReasonConcurrency{reasonBase: reasonBase{&pos}, Kind: ConcurrencyKindGo})
+		case *ast.Ident:
+			// Check if ident is for a non-deterministic var
+			if varType, _ := pass.TypesInfo.ObjectOf(n).(*types.Var); varType != nil {
+				var ignore NonDeterminisms
+				if pass.ImportObjectFact(varType, &ignore) {
+					c.debugf("Marking %v as non-determistic because it accesses %v.%v",
+						name, varType.Pkg().Path(), varType.Name())
 					pos := pass.Fset.Position(n.Pos())
-					reasons = append(reasons, &ReasonConcurrency{reasonBase: reasonBase{&pos}, Kind: ConcurrencyKindRecv})
+					reasons = append(reasons, &ReasonVarAccess{reasonBase: reasonBase{&pos}, Var: varType})
 				}
 			}
-			return true
-		})
+		case *ast.RangeStmt:
+			// Map and chan ranges are non-deterministic
+			rangeType := pass.TypesInfo.TypeOf(n.X)
+			// Unwrap named type
+			for {
+				if namedType, _ := rangeType.(*types.Named); namedType != nil {
+					rangeType = namedType.Underlying()
+				} else {
+					break
+				}
+			}
+			switch rangeType.(type) {
+			case *types.Map:
+				c.debugf("Marking %v as non-determistic because it iterates over a map", name)
+				pos := pass.Fset.Position(n.Pos())
+				reasons = append(reasons, &ReasonMapRange{reasonBase: reasonBase{&pos}})
+			case *types.Chan:
+				c.debugf("Marking %v as non-determistic because it iterates over a channel", name)
+				pos := pass.Fset.Position(n.Pos())
+				reasons = append(reasons, &ReasonConcurrency{reasonBase: reasonBase{&pos}, Kind: ConcurrencyKindRange})
+			}
+		case *ast.SendStmt:
+			// Any send statement is non-deterministic
+			c.debugf("Marking %v as non-determistic because it sends to a channel", name)
+			pos := pass.Fset.Position(n.Pos())
+			reasons = append(reasons, &ReasonConcurrency{reasonBase: reasonBase{&pos}, Kind: ConcurrencyKindSend})
+		case *ast.UnaryExpr:
+			// If the operator is a receive, it is non-deterministic
+			if n.Op == token.ARROW {
+				c.debugf("Marking %v as non-determistic because it receives from a channel", name)
+				pos := pass.Fset.Position(n.Pos())
+				reasons = append(reasons, &ReasonConcurrency{reasonBase: reasonBase{&pos}, Kind: ConcurrencyKindRecv})
+			}
+		}
+		return true
+	})
+	return
+}
+
+// allowlistTrusts returns true if allowlist mode is enabled and the package is
+// an allowlisted package outside of the standard library and the main module,
+// meaning its implementation is not walked.
+func (c *Checker) allowlistTrusts(pkg *types.Package) bool {
+	return c.Allowlist != nil && pkg != nil && !IsStandardPackage(pkg.Path()) && !c.inMainModule(pkg.Path()) &&
+		c.Allowlist.AllowsPackage(pkg.Path())
+}
+
+func (c *Checker) boundaryNonDeterminisms(pass *analysis.Pass, fn *types.Func) NonDeterminisms {
+	switch policy := c.BoundaryPolicies.PolicyFor(fn.Pkg().Path()); policy {
+	case BoundaryPolicyTrust:
+		return nil
+	case BoundaryPolicyDistrust:
+		pos := pass.Fset.Position(fn.Pos())
+		return NonDeterminisms{&ReasonOutsideBoundary{reasonBase: reasonBase{&pos}, Policy: policy}}
+	default:
+		if c.IdentRefs[fn.FullName()] {
+			pos := pass.Fset.Position(fn.Pos())
+			return NonDeterminisms{&ReasonDecl{reasonBase: reasonBase{&pos}}}
+		}
+		return nil
 	}
-	// Put the reasons fact on the func, even if it is empty
-	results[fn] = reasons
-	return reasons
 }
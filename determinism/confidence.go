@@ -0,0 +1,152 @@
+package determinism
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+)
+
+// Confidence is how likely a non-determinism is to be a real problem as
+// opposed to a false positive.
+type Confidence int
+
+const (
+	// ConfidenceLow is for reasons that are likely false positives, such as
+	// calls to functions outside of the module boundary or chains that pass
+	// through the implementation of standard library or third-party functions
+	// and are deeper than a few calls.
+	ConfidenceLow Confidence = iota
+	// ConfidenceMedium is for reasons that are often used deterministically,
+	// such as iterating over a map, or for certain reasons reached through the
+	// implementation of a standard library or third-party function.
+	ConfidenceMedium
+	// ConfidenceHigh is for reasons that are almost always a real problem,
+	// such as calling time.Now, accessing os.Stdout, or starting a goroutine,
+	// reached without passing through any standard library or third-party
+	// implementation.
+	ConfidenceHigh
+)
+
+// ParseConfidence parses "low", "medium", or "high" into a confidence.
+func ParseConfidence(str string) (Confidence, error) {
+	switch str {
+	case "low":
+		return ConfidenceLow, nil
+	case "medium":
+		return ConfidenceMedium, nil
+	case "high":
+		return ConfidenceHigh, nil
+	default:
+		return 0, fmt.Errorf("unknown confidence %q", str)
+	}
+}
+
+// String returns the confidence in the form accepted by ParseConfidence.
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	default:
+		return "<unknown-confidence>"
+	}
+}
+
+// ConfidenceReason is a Reason with its own base confidence.
+type ConfidenceReason interface {
+	Reason
+	Confidence() Confidence
+}
+
+// Chains deeper than this many calls have their confidence lowered.
+const confidenceMaxDepth = 3
+
+// Confidence returns the confidence of the given reason of non-determinism,
+// including its child reasons if it is a function call. This starts with a
+// confidence based on the kind of the reason at the end of the chain, which is
+// lowered if the chain is deeper than a few calls or if it passes through the
+// implementation of a function in the standard library or third-party code. Reasons from other checkers may provide their own base
+// confidence by implementing ConfidenceReason. If there are multiple chains,
+// the highest confidence is returned.
+func (c *Checker) Confidence(reason Reason) Confidence {
+	return c.chainConfidence(reason, 0, false)
+}
+
+func (c *Checker) chainConfidence(reason Reason, depth int, external bool) Confidence {
+	var conf Confidence
+	switch reason := reason.(type) {
+	case *ReasonFuncCall:
+		// A function only being declared non-deterministic is not passing
+		// through its implementation
+		if !external && c.isExternal(reason.Func) {
+			for _, child := range reason.Child {
+				if _, decl := child.(*ReasonDecl); !decl {
+					external = true
+					break
+				}
+			}
+		}
+		conf = ConfidenceLow
+		for _, child := range reason.Child {
+			if childConf := c.chainConfidence(child, depth+1, external); childConf > conf {
+				conf = childConf
+			}
+		}
+		return conf
+	case *ReasonDecl, *ReasonVarAccess, *ReasonConcurrency, *ReasonDisallowedCall:
+		conf = ConfidenceHigh
+	case *ReasonMapRange:
+		// Map iteration is often used in deterministic ways
+		conf = ConfidenceMedium
+	case *ReasonOutsideBoundary:
+		conf = ConfidenceLow
+	case ConfidenceReason:
+		conf = reason.Confidence()
+	default:
+		conf = ConfidenceMedium
+	}
+	if external {
+		conf--
+	}
+	if depth > confidenceMaxDepth {
+		conf--
+	}
+	if conf < ConfidenceLow {
+		conf = ConfidenceLow
+	}
+	return conf
+}
+
+// isExternal returns true if the function is in the standard library or is
+// third-party code, meaning outside of the module boundary if module boundary
+// mode is enabled or outside of the main module otherwise.
+func (c *Checker) isExternal(fn *types.Func) bool {
+	if fn.Pkg() == nil || IsStandardPackage(fn.Pkg().Path()) {
+		return true
+	} else if len(c.BoundaryModules) > 0 {
+		return !c.inBoundary(fn.Pkg().Path())
+	}
+	// Nothing is known to be third-party without a main module
+	return c.MainModule != "" && !c.inMainModule(fn.Pkg().Path())
+}
+
+type confidenceFlag struct{ confidence *Confidence }
+
+// NewConfidenceFlag creates a flag.Value implementation for using
+// ParseConfidence as a CLI flag value.
+func NewConfidenceFlag(confidence *Confidence) flag.Value { return confidenceFlag{confidence} }
+
+func (c confidenceFlag) String() string {
+	if c.confidence == nil {
+		return ConfidenceLow.String()
+	}
+	return c.confidence.String()
+}
+
+func (c confidenceFlag) Set(flag string) (err error) {
+	*c.confidence, err = ParseConfidence(flag)
+	return
+}
@@ -1,6 +1,9 @@
 package determinism_test
 
 import (
+	"io"
+	"regexp"
+	"runtime"
 	"testing"
 
 	"github.com/cretz/temporal-determinist/determinism"
@@ -32,3 +35,86 @@ func Test(t *testing.T) {
 		}
 	}
 }
+
+func TestAllowlist(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		determinism.NewChecker(determinism.Config{
+			Allowlist: determinism.Allowlist{
+				"example.com/allowlist/...": true,
+				"example.com/allowlisted":   true,
+				"strings":                   true,
+				"strings.Repeat":            false,
+				"time":                      true,
+				"os.IsNotExist":             true,
+			},
+			MainModule: "example.com/allowlist",
+		}).NewAnalyzer(),
+		"example.com/allowlist",
+	)
+}
+
+func TestBoundary(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		determinism.NewChecker(determinism.Config{
+			BoundaryModules: []string{"example.com/boundary"},
+			BoundaryPolicies: determinism.BoundaryPolicies{
+				"example.com/trusted":    determinism.BoundaryPolicyTrust,
+				"example.com/distrusted": determinism.BoundaryPolicyDistrust,
+			},
+		}).NewAnalyzer(),
+		"example.com/boundary",
+	)
+}
+
+func TestStdlib(t *testing.T) {
+	// Common standard library calls that are deterministic must never be marked
+	// as non-deterministic by the default ident refs
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		determinism.NewChecker(determinism.Config{}).NewAnalyzer(),
+		"stdlib",
+	)
+}
+
+// Go versions TestStdlib has been run on. After running it on a new Go version
+// and adding overrides to DefaultIdentRefs for any new false positives, add the
+// version here.
+var stdlibAuditedGoVersions = []string{"go1.27"}
+
+func TestStdlibGoVersion(t *testing.T) {
+	version := regexp.MustCompile(`^go1\.\d+`).FindString(runtime.Version())
+	if version == "" {
+		t.Skipf("unknown Go version %v", runtime.Version())
+	}
+	for _, audited := range stdlibAuditedGoVersions {
+		if version == audited {
+			return
+		}
+	}
+	t.Fatalf("standard library overrides not audited on %v, audited on %v", version, stdlibAuditedGoVersions)
+}
+
+func TestBoundaryDefault(t *testing.T) {
+	// The boundary flag is the module of this repository
+	checker := determinism.NewChecker(determinism.Config{})
+	analyzer := checker.NewAnalyzer()
+	if err := analyzer.Flags.Parse([]string{"-boundary", "-boundary-module", "example.com/other"}); err != nil {
+		t.Fatal(err)
+	}
+	modules := checker.BoundaryModules
+	if len(modules) != 2 || modules[0] != "github.com/cretz/temporal-determinist" || modules[1] != "example.com/other" {
+		t.Fatalf("unexpected boundary modules %v", modules)
+	}
+	// A module path given to the boundary flag is rejected instead of being
+	// treated as the main module
+	analyzer = determinism.NewChecker(determinism.Config{}).NewAnalyzer()
+	analyzer.Flags.SetOutput(io.Discard)
+	if err := analyzer.Flags.Parse([]string{"-boundary=example.com/other"}); err == nil {
+		t.Fatal("expected error for module path given to boundary flag")
+	}
+}
@@ -16,6 +16,23 @@ var DefaultIdentRefs = IdentRefs{
 	// We mark these as deterministic since they give so many false positives
 	"(reflect.Value).Interface": false,
 	"runtime.Caller":            false,
+	// The first GC started by any allocation (runtime.mallocgc -> gcStart)
+	// starts the background mark worker goroutines. This is reached from
+	// almost everything that allocates, e.g. fmt.Sscanf, encoding/xml.Marshal,
+	// and reflect.DeepEqual.
+	"runtime.gcBgMarkStartWorkers": false,
+	// The v1 JSON API is implemented on encoding/json/v2 which iterates over
+	// maps when marshaling them (v2.marshalObjectAny), but the v1 API always
+	// sorts map keys.
+	"encoding/json.Marshal":           false,
+	"encoding/json.Unmarshal":         false,
+	"(*encoding/json.Encoder).Encode": false,
+	"(*encoding/json.Decoder).Decode": false,
+	// Iterates over a map but sorts the keys before encoding
+	"(net/url.Values).Encode": false,
+	// Iterates over a map of parsed trees to add them, but the order they are
+	// added in does not matter
+	"(*text/template.Template).Parse": false,
 	// We are considering the global pseudorandom as non-deterministic by default
 	// since it's global (even if they set a seed), but we allow use of a manually
 	// instantiated random instance that may have a localized, fixed seed
@@ -0,0 +1,66 @@
+package determinism
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MainModulePath returns the module path of the go.mod file in the working
+// directory or the closest directory above it.
+func MainModulePath() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if path, err := modulePath(filepath.Join(dir, "go.mod")); err == nil {
+			return path, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod found in working directory or above")
+		}
+		dir = parent
+	}
+}
+
+func modulePath(goModFile string) (string, error) {
+	f, err := os.Open(goModFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "module ") && !strings.HasPrefix(line, "module\t") {
+			continue
+		}
+		path := strings.TrimSpace(line[len("module"):])
+		if comment := strings.Index(path, "//"); comment >= 0 {
+			path = strings.TrimSpace(path[:comment])
+		}
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			path = unquoted
+		}
+		if path != "" {
+			return path, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no module directive in %v", goModFile)
+}
+
+// inMainModule returns true if the package path is at or beneath the main
+// module path.
+func (c *Checker) inMainModule(path string) bool {
+	return c.MainModule != "" && (path == c.MainModule || strings.HasPrefix(path, c.MainModule+"/"))
+}
@@ -41,15 +41,7 @@ func (n NonDeterminisms) AppendChildReasonLines(
 	for _, reason := range n {
 		reasonStr := reason.String()
 		if includePos {
-			// Relativize path if it at least starts with working dir
-			pos := reason.Pos()
-			filename := pos.Filename
-			if wd, err := os.Getwd(); err == nil && strings.HasPrefix(filename, wd) {
-				if relFilename, err := filepath.Rel(wd, filename); err == nil {
-					filename = relFilename
-				}
-			}
-			reasonStr += fmt.Sprintf(" at %v:%v:%v", filename, pos.Line, pos.Column)
+			reasonStr += " at " + RelativePosition(*reason.Pos())
 		}
 		s = append(s, fmt.Sprintf("%v is non-deterministic, reason: %v", strings.Repeat("  ", depth)+subject, reasonStr))
 		// Recurse if func call
@@ -60,6 +52,18 @@ func (n NonDeterminisms) AppendChildReasonLines(
 	return s
 }
 
+// RelativePosition returns the position as a string with the filename relative
+// to the working directory if it at least starts with it.
+func RelativePosition(pos token.Position) string {
+	filename := pos.Filename
+	if wd, err := os.Getwd(); err == nil && strings.HasPrefix(filename, wd) {
+		if relFilename, err := filepath.Rel(wd, filename); err == nil {
+			filename = relFilename
+		}
+	}
+	return fmt.Sprintf("%v:%v:%v", filename, pos.Line, pos.Column)
+}
+
 // Reason represents a reason for non-determinism.
 type Reason interface {
 	Pos() *token.Position
@@ -91,6 +95,12 @@ type ReasonFuncCall struct {
 	Child NonDeterminisms
 }
 
+// NewReasonFuncCall creates a ReasonFuncCall at the given position. This is
+// for checkers building their own chains of reasons.
+func NewReasonFuncCall(pos *token.Position, fn *types.Func, child NonDeterminisms) *ReasonFuncCall {
+	return &ReasonFuncCall{reasonBase: reasonBase{pos}, Func: fn, Child: child}
+}
+
 // String returns the reason.
 func (r *ReasonFuncCall) String() string {
 	return "calls non-determistic function " + r.Func.FullName()
@@ -149,3 +159,27 @@ type ReasonMapRange struct {
 func (r *ReasonMapRange) String() string {
 	return "iterates over map"
 }
+
+// ReasonDisallowedCall represents a call to a function that is not in the
+// allowlist when allowlist mode is enabled.
+type ReasonDisallowedCall struct {
+	reasonBase
+	Func *types.Func
+}
+
+// String returns the reason.
+func (r *ReasonDisallowedCall) String() string {
+	return "calls non-allowlisted function " + r.Func.FullName()
+}
+
+// ReasonOutsideBoundary represents a function outside of the module boundary
+// whose policy considers it non-deterministic.
+type ReasonOutsideBoundary struct {
+	reasonBase
+	Policy BoundaryPolicy
+}
+
+// String returns the reason.
+func (r *ReasonOutsideBoundary) String() string {
+	return "outside module boundary with policy " + r.Policy.String()
+}
@@ -0,0 +1,46 @@
+package allowlist
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"example.com/allowlisted"
+	"example.com/other"
+)
+
+func CallsAllowedPackage() string {
+	return strings.ToUpper("foo")
+}
+
+func CallsAllowedPackageTransitively() string {
+	return CallsAllowedPackage()
+}
+
+func CallsDisallowedPackage() string { // want CallsDisallowedPackage:"calls non-allowlisted function os.Getenv"
+	return os.Getenv("FOO")
+}
+
+func CallsDisallowedPackageTransitively() string { // want CallsDisallowedPackageTransitively:"calls non-determistic function example.com/allowlist.CallsDisallowedPackage"
+	return CallsDisallowedPackage()
+}
+
+func CallsDisallowedFunc() string { // want CallsDisallowedFunc:"calls non-allowlisted function strings.Repeat"
+	return strings.Repeat("foo", 2)
+}
+
+func CallsAllowedFunc() bool {
+	return os.IsNotExist(nil)
+}
+
+func CallsOtherModule() string { // want CallsOtherModule:"calls non-allowlisted function example.com/other.Helper"
+	return other.Helper()
+}
+
+func CallsAllowedNonDeterministic() time.Time { // want CallsAllowedNonDeterministic:"calls non-determistic function time.Now"
+	return time.Now()
+}
+
+func CallsAllowedOtherModule() string {
+	return allowlisted.Helper()
+}
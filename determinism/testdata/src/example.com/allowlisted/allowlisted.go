@@ -0,0 +1,8 @@
+package allowlisted
+
+import "os"
+
+func Helper() string {
+	go func() {}()
+	return os.Getenv("FOO")
+}
@@ -0,0 +1,39 @@
+package boundary
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"example.com/distrusted"
+	"example.com/other"
+	"example.com/trusted"
+)
+
+func CallsStdlibWithoutRule() {
+	fmt.Println()
+}
+
+func CallsStdlibWithRule() { // want CallsStdlibWithRule:"calls non-determistic function time.Now"
+	time.Now()
+}
+
+func AccessesStdlibVar() { // want AccessesStdlibVar:"accesses non-determistic var os.Stdout"
+	os.Stdout.Write(nil)
+}
+
+func CallsTrusted() {
+	trusted.Now()
+}
+
+func CallsDistrusted() { // want CallsDistrusted:"calls non-determistic function example.com/distrusted.Pure"
+	distrusted.Pure()
+}
+
+func CallsOtherWithoutRule() {
+	other.Helper()
+}
+
+func CallsInsideTransitively() { // want CallsInsideTransitively:"calls non-determistic function example.com/boundary.CallsDistrusted"
+	CallsDistrusted()
+}
@@ -0,0 +1,5 @@
+package distrusted
+
+func Pure() int {
+	return 1
+}
@@ -0,0 +1,5 @@
+package other
+
+func Helper() string {
+	return "helper"
+}
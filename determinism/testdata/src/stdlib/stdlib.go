@@ -0,0 +1,139 @@
+package stdlib
+
+// Each function here calls a common standard library entry point that is
+// deterministic but may be flagged because of internal caches, pools, or
+// runtime internals depending on Go version. None of them should have facts.
+// The positive controls at the bottom use the same entry points with real
+// non-determinism to show the overrides do not hide it.
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/big"
+	"math/rand"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+func FmtSprintf()          { fmt.Sprintf("%v", 1) }
+func FmtSprint()           { fmt.Sprint(1) }
+func FmtErrorf()           { fmt.Errorf("%w", errors.New("x")) }
+func FmtSscanf()           { var i int; fmt.Sscanf("1", "%d", &i) }
+func FmtFprintf()          { fmt.Fprintf(&bytes.Buffer{}, "x") }
+func JSONMarshal()         { json.Marshal(map[string]interface{}{"x": 1}) }
+func JSONMarshalIndent()   { json.MarshalIndent(1, "", "") }
+func JSONUnmarshal()       { var i int; json.Unmarshal(nil, &i) }
+func JSONEncoderEncode()   { json.NewEncoder(&bytes.Buffer{}).Encode(1) }
+func JSONDecoderDecode()   { json.NewDecoder(&bytes.Buffer{}).Decode(nil) }
+func XMLMarshal()          { xml.Marshal(1) }
+func GobEncoderEncode()    { gob.NewEncoder(&bytes.Buffer{}).Encode(1) }
+func BinaryWrite()         { binary.Write(&bytes.Buffer{}, binary.BigEndian, int32(1)) }
+func Base64Encode()        { base64.StdEncoding.EncodeToString(nil) }
+func HexEncode()           { hex.EncodeToString(nil) }
+func RegexpMustCompile()   { regexp.MustCompile("x") }
+func RegexpCompile()       { regexp.Compile("x") }
+func RegexpMatchString()   { regexp.MustCompile("x").MatchString("x") }
+func RegexpReplaceAll()    { regexp.MustCompile("x").ReplaceAllString("x", "y") }
+func RegexpFindAll()       { regexp.MustCompile("x").FindAllString("x", -1) }
+func StrconvItoa()         { strconv.Itoa(1) }
+func StrconvAtoi()         { strconv.Atoi("1") }
+func StrconvParseInt()     { strconv.ParseInt("1", 10, 64) }
+func StrconvParseFloat()   { strconv.ParseFloat("1", 64) }
+func StrconvFormatFloat()  { strconv.FormatFloat(1, 'f', -1, 64) }
+func StrconvQuote()        { strconv.Quote("x") }
+func StrconvParseBool()    { strconv.ParseBool("x") }
+func ErrorsAs()            { var e *url.Error; errors.As(errors.New("x"), &e) }
+func ErrorsIs()            { errors.Is(nil, nil) }
+func ErrorsUnwrap()        { errors.Unwrap(nil) }
+func StringsBuilder()      { var b strings.Builder; b.WriteString("x"); _ = b.String() }
+func StringsSplit()        { strings.Split("x", ",") }
+func StringsReplacer()     { strings.NewReplacer("a", "b").Replace("x") }
+func StringsFields()       { strings.Fields("x") }
+func SortSlice()           { sort.Slice([]int{}, func(i, j int) bool { return false }) }
+func SortStrings()         { sort.Strings(nil) }
+func BytesBuffer()         { var b bytes.Buffer; b.WriteString("x") }
+func BufioScanner()        { bufio.NewScanner(&bytes.Buffer{}).Scan() }
+func IoReadAll()           { io.ReadAll(&bytes.Buffer{}) }
+func BigInt()              { big.NewInt(1).String() }
+func MathSqrt()            { math.Sqrt(2) }
+func RandNewSource()       { rand.New(rand.NewSource(1)).Int() }
+func Sha256Sum()           { sha256.Sum256(nil) }
+func FnvHash()             { fnv.New32a().Sum32() }
+func URLParse()            { url.Parse("x") }
+func URLQueryEscape()      { url.QueryEscape("x") }
+func URLValuesEncode()     { url.Values{"x": {"y"}}.Encode() }
+func ReflectTypeOf()       { reflect.TypeOf(1).String() }
+func ReflectDeepEqual()    { reflect.DeepEqual(1, 1) }
+func ReflectInterface()    { reflect.ValueOf(1).Interface() }
+func ReflectMethodByName() { reflect.ValueOf(1).MethodByName("x") }
+func TemplateParse()       { template.Must(template.New("x").Parse("x")).Execute(&bytes.Buffer{}, nil) }
+func TimeDurationString()  { time.Duration(1).String() }
+func TimeParse()           { time.Parse(time.RFC3339, "x") }
+func TimeParseDuration()   { time.ParseDuration("1s") }
+func TimeFormat()          { time.Time{}.Format(time.RFC3339) }
+func TimeUnix()            { time.Unix(0, 0) }
+func TimeDate()            { time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC) }
+func UTF8RuneCount()       { utf8.RuneCountInString("x") }
+func PathJoin()            { path.Join("a") }
+func FilepathJoin()        { filepath.Join("a") }
+
+// Positive controls for runtime.gcBgMarkStartWorkers
+
+func FmtFprintfStdout() { fmt.Fprintf(os.Stdout, "x") } // want FmtFprintfStdout:"accesses non-determistic var os.Stdout"
+func XMLMarshalNow()    { xml.Marshal(time.Now()) }     // want XMLMarshalNow:"calls non-determistic function time.Now"
+func ReflectDeepEqualGoroutine() { // want ReflectDeepEqualGoroutine:"starts goroutine"
+	reflect.DeepEqual(1, 1)
+	go func() {}()
+}
+
+// Positive controls for encoding/json
+
+func JSONMarshalNow()    { json.Marshal(time.Now()) }              // want JSONMarshalNow:"calls non-determistic function time.Now"
+func JSONEncoderStdout() { json.NewEncoder(os.Stdout).Encode(1) }  // want JSONEncoderStdout:"accesses non-determistic var os.Stdout"
+func JSONDecoderStdin()  { json.NewDecoder(os.Stdin).Decode(nil) } // want JSONDecoderStdin:"accesses non-determistic var os.Stdin"
+func JSONUnmarshalMapRange() { // want JSONUnmarshalMapRange:"iterates over map"
+	var m map[string][]byte
+	for _, b := range m {
+		var i int
+		json.Unmarshal(b, &i)
+	}
+}
+
+// Positive control for (net/url.Values).Encode
+
+func URLValuesEncodeNow() { url.Values{"x": {time.Now().String()}}.Encode() } // want URLValuesEncodeNow:"calls non-determistic function time.Now"
+
+// Positive control for (*text/template.Template).Parse
+
+func TemplateExecuteStdout() { template.Must(template.New("x").Parse("x")).Execute(os.Stdout, nil) } // want TemplateExecuteStdout:"accesses non-determistic var os.Stdout"
+
+// Positive controls for (reflect.Value).Interface and runtime.Caller
+
+func ReflectInterfaceStdout() { reflect.ValueOf(os.Stdout).Interface() } // want ReflectInterfaceStdout:"accesses non-determistic var os.Stdout"
+func RuntimeCallerNow() { // want RuntimeCallerNow:"calls non-determistic function time.Now"
+	runtime.Caller(0)
+	time.Now()
+}
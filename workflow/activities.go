@@ -0,0 +1,238 @@
+package workflow
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/cretz/temporal-determinist/determinism"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// WorkflowUses is the object fact of uses of workflow-only APIs by a
+// function, directly or transitively.
+type WorkflowUses []*WorkflowUse
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*WorkflowUses) AFact() {}
+
+// String returns all uses as a comma-delimited string.
+func (w *WorkflowUses) String() string {
+	if w == nil {
+		return "<none>"
+	}
+	strs := make([]string, len(*w))
+	for i, use := range *w {
+		strs[i] = use.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// AppendChildUseLines appends to lines the set of uses in this slice. This
+// will include newlines and indention based on depth the same way as
+// determinism.NonDeterminisms.AppendChildReasonLines.
+func (w WorkflowUses) AppendChildUseLines(subject string, s []string, depth int, includePos bool) []string {
+	for _, use := range w {
+		useStr := use.String()
+		if includePos {
+			useStr += " at " + determinism.RelativePosition(use.Pos)
+		}
+		s = append(s, fmt.Sprintf("%v uses workflow-only APIs, reason: %v", strings.Repeat("  ", depth)+subject, useStr))
+		// Recurse if func call
+		if use.Func != nil {
+			s = use.Child.AppendChildUseLines(use.Func.FullName(), s, depth+1, includePos)
+		}
+	}
+	return s
+}
+
+// WorkflowUse is a call to a workflow-only API, to a function that uses one,
+// or to a function given a workflow.Context.
+type WorkflowUse struct {
+	Pos token.Position
+	// The workflow-only API called directly if Func and ContextTo are nil
+	API *types.Func
+	// The function called that uses workflow-only APIs
+	Func  *types.Func
+	Child WorkflowUses
+	// The function called with a workflow.Context argument or taking one
+	ContextTo *types.Func
+}
+
+// String returns the use, not including any child uses.
+func (w *WorkflowUse) String() string {
+	if w.Func != nil {
+		return "calls function " + w.Func.FullName()
+	} else if w.ContextTo != nil {
+		return "passes workflow.Context to " + w.ContextTo.FullName()
+	}
+	return "calls workflow API " + w.API.FullName()
+}
+
+// ActivityConfig is config for NewActivityChecker.
+type ActivityConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+	// If set, the file and line/col position is present on nested errors.
+	IncludePosOnMessage bool
+}
+
+// ActivityChecker checks that functions registered as activities do not use
+// workflow-only APIs, directly or transitively.
+type ActivityChecker struct {
+	*checkerDebug
+	IncludePosOnMessage bool
+}
+
+// NewActivityChecker creates an ActivityChecker for the given config.
+func NewActivityChecker(config ActivityConfig) *ActivityChecker {
+	// Build checker
+	return &ActivityChecker{
+		checkerDebug:        newCheckerDebug(config.DebugfFunc, config.Debug),
+		IncludePosOnMessage: config.IncludePosOnMessage,
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is an -activity-debug flag for enabling debug logs and a
+// -show-pos flag for showing position on nested errors. This analyzer does not
+// have any results but does set *WorkflowUses facts on functions and *VarFuncs
+// facts on package-level vars.
+func (c *ActivityChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "activity",
+		Doc:       "Analyzes all RegisterActivity functions for use of workflow-only APIs",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&WorkflowUses{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "activity-debug", c.Debug, "show activity debug output")
+	a.Flags.BoolVar(&c.IncludePosOnMessage, "show-pos", c.IncludePosOnMessage,
+		"show file positions on workflow API use messages")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *ActivityChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false), c.IncludePosOnMessage)
+}
+
+func (c *ActivityChecker) run(p *checkedPackage, includePos bool) error {
+	pass := p.pass
+	// The SDK itself legitimately uses workflow APIs
+	if !callGraphPackage(pass.Pkg) {
+		return nil
+	}
+	c.debugf("Checking activities of package %v", pass.Pkg.Path())
+	f := &workflowUseFinder{
+		ActivityChecker: c,
+		pass:            pass,
+		funcDecls:       p.resolver.funcDecls,
+		results:         map[*types.Func]WorkflowUses{},
+		walking:         map[*types.Func]bool{},
+	}
+	// Set facts for every function using workflow APIs
+	for fn := range f.funcDecls {
+		f.funcUses(fn)
+	}
+	// Report each use by each activity
+	for _, activity := range p.registeredActivities() {
+		var uses WorkflowUses
+		p.rootFact(activity, &uses, func(lit *ast.FuncLit) { uses = f.nodeUses(lit.Body) })
+		// Activities are not given a workflow.Context to use
+		if sig := activity.signature(pass); sig != nil {
+			for i := 0; i < sig.Params().Len(); i++ {
+				if isWorkflowContext(sig.Params().At(i).Type()) {
+					pass.Reportf(activity.pos, "activity %v uses workflow-only APIs, reason: has workflow.Context parameter %v",
+						activity.name, sig.Params().At(i).Name())
+				}
+			}
+		}
+		for _, use := range uses {
+			lines := WorkflowUses{use}.AppendChildUseLines("activity "+activity.name, nil, 0, includePos)
+			pass.Reportf(activity.pos, "%v", strings.Join(lines, "\n"))
+		}
+	}
+	return nil
+}
+
+type workflowUseFinder struct {
+	*ActivityChecker
+	pass      *analysis.Pass
+	funcDecls map[*types.Func]*ast.FuncDecl
+	// Uses of functions in this package already found
+	results map[*types.Func]WorkflowUses
+	// Functions currently being walked to prevent recursion
+	walking map[*types.Func]bool
+}
+
+// funcUses returns the workflow API uses of the given function in this
+// package, setting the fact if there are any and the function does not take a
+// workflow context.
+func (f *workflowUseFinder) funcUses(fn *types.Func) WorkflowUses {
+	if uses, ok := f.results[fn]; ok {
+		return uses
+	} else if f.walking[fn] || f.funcDecls[fn] == nil || f.funcDecls[fn].Body == nil {
+		return nil
+	}
+	f.walking[fn] = true
+	defer delete(f.walking, fn)
+	uses := f.nodeUses(f.funcDecls[fn].Body)
+	f.results[fn] = uses
+	// Functions taking a workflow context are workflow code, so calls to them
+	// from activities are reported without their uses
+	if len(uses) > 0 && !takesWorkflowContext(fn) {
+		f.debugf("Marking %v as using workflow APIs", fn.FullName())
+		f.pass.ExportObjectFact(fn, &uses)
+	}
+	return uses
+}
+
+// nodeUses returns the workflow API uses in the given node.
+func (f *workflowUseFinder) nodeUses(node ast.Node) (uses WorkflowUses) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		callExpr, _ := n.(*ast.CallExpr)
+		if callExpr == nil {
+			return true
+		}
+		callee := typeutil.StaticCallee(f.pass.TypesInfo, callExpr)
+		if callee == nil || callee.Pkg() == nil {
+			return true
+		}
+		if callee.Pkg().Path() == "go.temporal.io/sdk/workflow" {
+			uses = append(uses, &WorkflowUse{Pos: f.pass.Fset.Position(callExpr.Pos()), API: callee})
+			return true
+		}
+		var calleeUses WorkflowUses
+		switch {
+		case takesWorkflowContext(callee):
+			// Workflow code is reported as passing the context below without
+			// following its uses
+		case callee.Pkg() == f.pass.Pkg:
+			calleeUses = f.funcUses(callee)
+		default:
+			f.pass.ImportObjectFact(callee, &calleeUses)
+		}
+		if len(calleeUses) > 0 {
+			uses = append(uses, &WorkflowUse{Pos: f.pass.Fset.Position(callExpr.Pos()), Func: callee, Child: calleeUses})
+			return true
+		}
+		passesContext := takesWorkflowContext(callee)
+		for _, arg := range callExpr.Args {
+			if t := f.pass.TypesInfo.TypeOf(arg); t != nil && isWorkflowContext(t) {
+				passesContext = true
+				break
+			}
+		}
+		if passesContext {
+			uses = append(uses, &WorkflowUse{Pos: f.pass.Fset.Position(callExpr.Pos()), ContextTo: callee})
+		}
+		return true
+	})
+	return
+}
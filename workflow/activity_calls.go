@@ -0,0 +1,264 @@
+package workflow
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/cretz/temporal-determinist/determinism"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Callee is a function called directly at a position.
+type Callee struct {
+	Func *types.Func
+	Pos  token.Pos
+	// Callees of the function if it is in the same package as the caller,
+	// since facts of unexported functions are not visible to other packages
+	Callees Callees
+}
+
+// Callees is the object fact of functions a function calls directly, sorted by
+// position. Only the first call to each function is present. Functions in the
+// standard library or the Temporal SDK are never present. Only exported
+// functions in packages that import the Temporal SDK, directly or
+// transitively, have this fact.
+type Callees []*Callee
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*Callees) AFact() {}
+
+// String returns all callee function names as a comma-delimited string.
+func (c *Callees) String() string {
+	if c == nil {
+		return "<none>"
+	}
+	names := make([]string, len(*c))
+	for i, callee := range *c {
+		names[i] = callee.Func.FullName()
+	}
+	return strings.Join(names, ", ")
+}
+
+// RegisteredActivities is the package fact of functions registered as
+// activities in a package or any of its dependencies.
+type RegisteredActivities map[*types.Func]bool
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*RegisteredActivities) AFact() {}
+
+// String returns all activity function names sorted and comma-delimited.
+func (r *RegisteredActivities) String() string {
+	if r == nil {
+		return "<none>"
+	}
+	names := make([]string, 0, len(*r))
+	for fn := range *r {
+		names = append(names, fn.FullName())
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// ReasonActivityCall represents calling a registered activity function
+// directly instead of executing it as an activity.
+type ReasonActivityCall struct {
+	pos  *token.Position
+	Func *types.Func
+}
+
+// Pos returns the position of the call.
+func (r *ReasonActivityCall) Pos() *token.Position { return r.pos }
+
+// String returns the reason.
+func (r *ReasonActivityCall) String() string {
+	return "calls activity function " + r.Func.FullName() + " directly"
+}
+
+// Confidence is always high since the activity is known to be registered.
+func (r *ReasonActivityCall) Confidence() determinism.Confidence { return determinism.ConfidenceHigh }
+
+// callGraphPackage returns true if functions in the package may have Callees,
+// meaning it is not in the standard library or the Temporal SDK.
+func callGraphPackage(pkg *types.Package) bool {
+	return pkg != nil && !determinism.IsStandardPackage(pkg.Path()) && !isSDKPackage(pkg.Path())
+}
+
+// isSDKPackage returns true if the package path is in the Temporal SDK.
+func isSDKPackage(path string) bool {
+	return path == "go.temporal.io/sdk" || strings.HasPrefix(path, "go.temporal.io/sdk/")
+}
+
+// calleeFinder finds callees of functions in a package.
+type calleeFinder struct {
+	pass      *analysis.Pass
+	funcDecls map[*types.Func]*ast.FuncDecl
+	// Callees of functions in this package already found
+	results map[*types.Func]Callees
+	// Functions currently being walked to prevent recursion
+	walking map[*types.Func]bool
+}
+
+func newCalleeFinder(p *checkedPackage) *calleeFinder {
+	return &calleeFinder{
+		pass:      p.pass,
+		funcDecls: p.resolver.funcDecls,
+		results:   map[*types.Func]Callees{},
+		walking:   map[*types.Func]bool{},
+	}
+}
+
+// funcCallees returns the callees of the given function in this package.
+func (f *calleeFinder) funcCallees(fn *types.Func) Callees {
+	if callees, ok := f.results[fn]; ok {
+		return callees
+	} else if f.walking[fn] || f.funcDecls[fn] == nil || f.funcDecls[fn].Body == nil {
+		return nil
+	}
+	f.walking[fn] = true
+	defer delete(f.walking, fn)
+	callees := f.nodeCallees(f.funcDecls[fn].Body)
+	f.results[fn] = callees
+	return callees
+}
+
+// nodeCallees returns the static callees in the given node that may have
+// Callees.
+func (f *calleeFinder) nodeCallees(node ast.Node) (callees Callees) {
+	seen := map[*types.Func]bool{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if callExpr, _ := n.(*ast.CallExpr); callExpr != nil {
+			if fn := typeutil.StaticCallee(f.pass.TypesInfo, callExpr); fn != nil && callGraphPackage(fn.Pkg()) && !seen[fn] {
+				seen[fn] = true
+				callee := &Callee{Func: fn, Pos: callExpr.Pos()}
+				if fn.Pkg() == f.pass.Pkg {
+					callee.Callees = f.funcCallees(fn)
+				}
+				callees = append(callees, callee)
+			}
+		}
+		return true
+	})
+	sort.Slice(callees, func(i, j int) bool { return callees[i].Pos < callees[j].Pos })
+	return
+}
+
+// importsSDK returns true if the package imports the Temporal SDK directly or
+// transitively. Results are cached in the given map.
+func importsSDK(pkg *types.Package, cache map[*types.Package]bool) bool {
+	if imports, ok := cache[pkg]; ok {
+		return imports
+	}
+	// Assume not while walking to prevent recursion
+	cache[pkg] = false
+	for _, imp := range pkg.Imports() {
+		if isSDKPackage(imp.Path()) || importsSDK(imp, cache) {
+			cache[pkg] = true
+			break
+		}
+	}
+	return cache[pkg]
+}
+
+// exportCallees exports the Callees fact for every exported function declared
+// in the package that calls another if the package imports the Temporal SDK.
+// Unexported functions are only present nested in the Callees of functions
+// calling them.
+func exportCallees(p *checkedPackage, f *calleeFinder) {
+	pass := p.pass
+	if !callGraphPackage(pass.Pkg) || !importsSDK(pass.Pkg, map[*types.Package]bool{}) {
+		return
+	}
+	for fn := range f.funcDecls {
+		if !fn.Exported() {
+			continue
+		}
+		if callees := f.funcCallees(fn); len(callees) > 0 {
+			pass.ExportObjectFact(fn, &callees)
+		}
+	}
+}
+
+// registeredActivities returns the functions registered as activities in the
+// package or any of its dependencies and exports them as a fact.
+func registeredActivities(p *checkedPackage) RegisteredActivities {
+	pass := p.pass
+	activities := RegisteredActivities{}
+	for _, imp := range pass.Pkg.Imports() {
+		var impActivities RegisteredActivities
+		if pass.ImportPackageFact(imp, &impActivities) {
+			for fn := range impActivities {
+				activities[fn] = true
+			}
+		}
+	}
+	for _, activity := range p.registeredActivities() {
+		if activity.fn != nil {
+			activities[activity.fn] = true
+		}
+	}
+	if len(activities) > 0 {
+		pass.ExportPackageFact(&activities)
+	}
+	return activities
+}
+
+// activityCallReasons returns a reason for each activity the root calls
+// directly or transitively. The shortest chain of calls is used for each.
+func activityCallReasons(
+	p *checkedPackage,
+	f *calleeFinder,
+	root *root,
+	activities map[*types.Func]bool,
+) determinism.NonDeterminisms {
+	pass := p.pass
+	var rootCallees Callees
+	if root.lit != nil {
+		rootCallees = f.nodeCallees(root.lit.Body)
+	} else if root.fn.Pkg() == pass.Pkg {
+		rootCallees = f.funcCallees(root.fn)
+	} else {
+		pass.ImportObjectFact(root.fn, &rootCallees)
+	}
+	// Breadth-first search keeping the call that first reached each function
+	type step struct {
+		callee *Callee
+		parent *step
+	}
+	visited := map[*types.Func]bool{root.fn: true}
+	queue := make([]*step, 0, len(rootCallees))
+	for _, callee := range rootCallees {
+		queue = append(queue, &step{callee: callee})
+	}
+	var reasons determinism.NonDeterminisms
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		if visited[curr.callee.Func] {
+			continue
+		}
+		visited[curr.callee.Func] = true
+		if !activities[curr.callee.Func] {
+			callees := curr.callee.Callees
+			if callees == nil {
+				pass.ImportObjectFact(curr.callee.Func, &callees)
+			}
+			for _, callee := range callees {
+				queue = append(queue, &step{callee: callee, parent: curr})
+			}
+			continue
+		}
+		// Build the chain from the activity call back up to the root
+		pos := pass.Fset.Position(curr.callee.Pos)
+		var reason determinism.Reason = &ReasonActivityCall{pos: &pos, Func: curr.callee.Func}
+		for s := curr.parent; s != nil; s = s.parent {
+			pos := pass.Fset.Position(s.callee.Pos)
+			reason = determinism.NewReasonFuncCall(&pos, s.callee.Func, determinism.NonDeterminisms{reason})
+		}
+		reasons = append(reasons, reason)
+	}
+	return reasons
+}
@@ -0,0 +1,362 @@
+package workflow
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// SharedWrites is the object fact of writes a method makes to state shared
+// through its receiver without holding a mutex, directly or through other
+// methods called on the receiver.
+type SharedWrites []*SharedWrite
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*SharedWrites) AFact() {}
+
+// String returns all writes as a comma-delimited string.
+func (s *SharedWrites) String() string {
+	if s == nil {
+		return "<none>"
+	}
+	strs := make([]string, len(*s))
+	for i, write := range *s {
+		strs[i] = write.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// SharedWrite is a write to state shared through a receiver.
+type SharedWrite struct {
+	Pos token.Position
+	// Path of the write from the receiver, e.g. "cache[]" or "stats.count"
+	Field string
+	// The method called on the receiver that makes the write if not made
+	// directly
+	Via *types.Func
+}
+
+// String returns the write.
+func (s *SharedWrite) String() string {
+	if s.Via != nil {
+		return "writes " + s.Field + " via " + s.Via.FullName()
+	}
+	return "writes " + s.Field
+}
+
+// mutexLockFuncs are the qualified names of methods that acquire or release an
+// exclusive lock with whether the lock is held after the call.
+var mutexLockFuncs = map[string]bool{
+	"(*sync.Mutex).Lock":     true,
+	"(*sync.Mutex).Unlock":   false,
+	"(*sync.RWMutex).Lock":   true,
+	"(*sync.RWMutex).Unlock": false,
+}
+
+// ActivityStateConfig is config for NewActivityStateChecker.
+type ActivityStateConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// ActivityStateChecker checks that methods registered as activities do not
+// write state shared through their receiver without holding a mutex, since
+// one receiver is shared across all concurrently executing activities.
+type ActivityStateChecker struct {
+	*checkerDebug
+}
+
+// NewActivityStateChecker creates an ActivityStateChecker for the given
+// config.
+func NewActivityStateChecker(config ActivityStateConfig) *ActivityStateChecker {
+	// Build checker
+	return &ActivityStateChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is an -activity-state-debug flag for enabling debug logs. This
+// analyzer does not have any results but does set *SharedWrites facts on
+// methods and *VarFuncs facts on package-level vars.
+func (c *ActivityStateChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "activitystate",
+		Doc:       "Analyzes methods registered as activities for unprotected writes to shared receiver state",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&SharedWrites{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "activity-state-debug", c.Debug, "show activity state debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *ActivityStateChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *ActivityStateChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	if !callGraphPackage(pass.Pkg) {
+		return nil
+	}
+	c.debugf("Checking activity state of package %v", pass.Pkg.Path())
+	f := &sharedWriteFinder{
+		ActivityStateChecker: c,
+		pass:                 pass,
+		methodDecls:          map[*types.Func]*ast.FuncDecl{},
+		results:              map[*types.Func]SharedWrites{},
+		walking:              map[*types.Func]bool{},
+	}
+	for fn, funcDecl := range p.resolver.funcDecls {
+		if funcDecl.Recv != nil && funcDecl.Body != nil {
+			f.methodDecls[fn] = funcDecl
+		}
+	}
+	// Set facts for every method with shared writes
+	for fn := range f.methodDecls {
+		f.methodWrites(fn)
+	}
+	// Report each write of each activity method
+	for _, activity := range p.registeredActivities() {
+		var writes SharedWrites
+		p.rootFact(activity, &writes, nil)
+		for _, write := range writes {
+			if write.Via != nil {
+				pass.Reportf(activity.pos, "activity %v writes shared receiver state %v without holding a mutex via %v",
+					activity.name, write.Field, write.Via.FullName())
+			} else {
+				pass.Reportf(activity.pos, "activity %v writes shared receiver state %v without holding a mutex",
+					activity.name, write.Field)
+			}
+		}
+	}
+	return nil
+}
+
+type sharedWriteFinder struct {
+	*ActivityStateChecker
+	pass        *analysis.Pass
+	methodDecls map[*types.Func]*ast.FuncDecl
+	// Writes of methods in this package already found
+	results map[*types.Func]SharedWrites
+	// Methods currently being walked to prevent recursion
+	walking map[*types.Func]bool
+}
+
+// methodWrites returns the unprotected shared writes of the given method in
+// this package, setting the fact if there are any.
+func (f *sharedWriteFinder) methodWrites(fn *types.Func) SharedWrites {
+	if writes, ok := f.results[fn]; ok {
+		return writes
+	}
+	funcDecl := f.methodDecls[fn]
+	if funcDecl == nil || f.walking[fn] || len(funcDecl.Recv.List) == 0 || len(funcDecl.Recv.List[0].Names) == 0 {
+		return nil
+	}
+	recv, _ := f.pass.TypesInfo.ObjectOf(funcDecl.Recv.List[0].Names[0]).(*types.Var)
+	if recv == nil {
+		return nil
+	}
+	f.walking[fn] = true
+	defer delete(f.walking, fn)
+	w := &sharedWriteWalker{sharedWriteFinder: f, recv: recv, seen: map[string]bool{}}
+	w.walkStmts(funcDecl.Body.List, false)
+	writes := w.writes
+	f.results[fn] = writes
+	if len(writes) > 0 {
+		f.debugf("Marking %v as writing shared receiver state", fn.FullName())
+		f.pass.ExportObjectFact(fn, &writes)
+	}
+	return writes
+}
+
+// sharedWriteWalker walks a method body for shared writes, tracking whether a
+// mutex reached through the receiver is held. A lock or unlock only applies to
+// the rest of the block it is in, and a deferred unlock keeps the lock held.
+type sharedWriteWalker struct {
+	*sharedWriteFinder
+	recv   *types.Var
+	writes SharedWrites
+	// Field and via of writes already added to skip duplicates
+	seen map[string]bool
+}
+
+func (w *sharedWriteWalker) walkStmts(stmts []ast.Stmt, locked bool) {
+	for _, stmt := range stmts {
+		if exprStmt, _ := stmt.(*ast.ExprStmt); exprStmt != nil {
+			if lockedAfter, ok := w.lockCall(exprStmt.X); ok {
+				locked = lockedAfter
+				continue
+			}
+		}
+		w.walk(stmt, locked)
+	}
+}
+
+func (w *sharedWriteWalker) walk(node ast.Node, locked bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			w.walkStmts(n.List, locked)
+			return false
+		case *ast.CaseClause:
+			for _, expr := range n.List {
+				w.walk(expr, locked)
+			}
+			w.walkStmts(n.Body, locked)
+			return false
+		case *ast.CommClause:
+			if n.Comm != nil {
+				w.walk(n.Comm, locked)
+			}
+			w.walkStmts(n.Body, locked)
+			return false
+		case *ast.GoStmt:
+			// Goroutines do not run under the lock of the caller
+			w.walk(n.Call, false)
+			return false
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE && !locked {
+				for _, lhs := range n.Lhs {
+					w.addWrite(lhs, "")
+				}
+			}
+		case *ast.IncDecStmt:
+			if !locked {
+				w.addWrite(n.X, "")
+			}
+		case *ast.CallExpr:
+			if !locked {
+				w.addCallWrites(n)
+			}
+		}
+		return true
+	})
+}
+
+// lockCall returns whether the lock is held after the given expression and
+// true if it is a call locking or unlocking a mutex reached through the
+// receiver.
+func (w *sharedWriteWalker) lockCall(expr ast.Expr) (locked bool, ok bool) {
+	callExpr, _ := expr.(*ast.CallExpr)
+	if callExpr == nil {
+		return false, false
+	}
+	sel, _ := callExpr.Fun.(*ast.SelectorExpr)
+	if sel == nil {
+		return false, false
+	}
+	callee, _ := typeutil.Callee(w.pass.TypesInfo, callExpr).(*types.Func)
+	if callee == nil {
+		return false, false
+	}
+	locked, ok = mutexLockFuncs[callee.FullName()]
+	if !ok {
+		return false, false
+	}
+	// The mutex may be embedded in the receiver or a field reachable from it
+	if ident := calleeIdent(sel.X); ident != nil && w.pass.TypesInfo.ObjectOf(ident) == w.recv {
+		return locked, true
+	} else if path, _ := w.receiverPath(sel.X, w.recv); path != "" {
+		return locked, true
+	}
+	return false, false
+}
+
+// addCallWrites adds the writes of a delete call on receiver state or of a
+// method called on the receiver.
+func (w *sharedWriteWalker) addCallWrites(callExpr *ast.CallExpr) {
+	// Deleting from a map is a write to it
+	if ident := calleeIdent(callExpr.Fun); ident != nil && len(callExpr.Args) > 0 {
+		if _, isBuiltin := w.pass.TypesInfo.ObjectOf(ident).(*types.Builtin); isBuiltin && ident.Name == "delete" {
+			w.addWrite(callExpr.Args[0], "[]")
+			return
+		}
+	}
+	// Methods called on the receiver itself
+	sel, _ := callExpr.Fun.(*ast.SelectorExpr)
+	if sel == nil {
+		return
+	}
+	if ident := calleeIdent(sel.X); ident == nil || w.pass.TypesInfo.ObjectOf(ident) != w.recv {
+		return
+	}
+	callee, _ := typeutil.Callee(w.pass.TypesInfo, callExpr).(*types.Func)
+	if callee == nil {
+		return
+	}
+	var calleeWrites SharedWrites
+	if callee.Pkg() == w.pass.Pkg {
+		calleeWrites = w.methodWrites(callee)
+	} else {
+		w.pass.ImportObjectFact(callee, &calleeWrites)
+	}
+	for _, write := range calleeWrites {
+		w.add(&SharedWrite{Pos: w.pass.Fset.Position(callExpr.Pos()), Field: write.Field, Via: callee})
+	}
+}
+
+// addWrite adds a write to the given expression if it is shared receiver
+// state.
+func (w *sharedWriteWalker) addWrite(expr ast.Expr, suffix string) {
+	if path, shared := w.receiverPath(expr, w.recv); path != "" && shared {
+		w.add(&SharedWrite{Pos: w.pass.Fset.Position(expr.Pos()), Field: path + suffix})
+	}
+}
+
+func (w *sharedWriteWalker) add(write *SharedWrite) {
+	key := write.String()
+	if !w.seen[key] {
+		w.seen[key] = true
+		w.writes = append(w.writes, write)
+	}
+}
+
+// receiverPath returns the path from the receiver to the given expression and
+// whether the state at the path is shared with other copies of the receiver.
+// The path is empty if the expression is not a field or element reachable from
+// the receiver.
+func (f *sharedWriteFinder) receiverPath(expr ast.Expr, recv *types.Var) (path string, shared bool) {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return f.receiverPath(expr.X, recv)
+	case *ast.SelectorExpr:
+		sel := f.pass.TypesInfo.Selections[expr]
+		if sel == nil || sel.Kind() != types.FieldVal {
+			return "", false
+		}
+		if ident := calleeIdent(expr.X); ident != nil {
+			if f.pass.TypesInfo.ObjectOf(ident) != recv {
+				return "", false
+			}
+			return expr.Sel.Name, sel.Indirect()
+		}
+		path, shared = f.receiverPath(expr.X, recv)
+		if path == "" {
+			return "", false
+		}
+		return path + "." + expr.Sel.Name, shared || sel.Indirect()
+	case *ast.IndexExpr:
+		path, shared = f.receiverPath(expr.X, recv)
+		if path == "" {
+			return "", false
+		}
+		switch f.pass.TypesInfo.TypeOf(expr.X).Underlying().(type) {
+		case *types.Map, *types.Slice, *types.Pointer:
+			shared = true
+		}
+		return path + "[]", shared
+	case *ast.StarExpr:
+		path, _ = f.receiverPath(expr.X, recv)
+		return path, path != ""
+	}
+	return "", false
+}
@@ -0,0 +1,333 @@
+package workflow
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// executeCalls are the qualified names of functions that execute activities
+// or child workflows with what is executed and the index of the function
+// argument. The arguments to the function follow it.
+var executeCalls = map[string]struct {
+	kind    string
+	fnIndex int
+}{
+	"go.temporal.io/sdk/workflow.ExecuteActivity":      {"activity", 1},
+	"go.temporal.io/sdk/internal.ExecuteActivity":      {"activity", 1},
+	"go.temporal.io/sdk/workflow.ExecuteLocalActivity": {"activity", 1},
+	"go.temporal.io/sdk/internal.ExecuteLocalActivity": {"activity", 1},
+	"go.temporal.io/sdk/workflow.ExecuteChildWorkflow": {"child workflow", 1},
+	"go.temporal.io/sdk/internal.ExecuteChildWorkflow": {"child workflow", 1},
+}
+
+// futureGetFuncs are the qualified names of the methods that get the result of
+// a future.
+var futureGetFuncs = map[string]bool{
+	"(go.temporal.io/sdk/workflow.Future).Get": true,
+	"(go.temporal.io/sdk/internal.Future).Get": true,
+}
+
+// ArgumentConfig is config for NewArgumentChecker.
+type ArgumentConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// ArgumentChecker checks that the arguments given when executing activities
+// and child workflows match the parameters of the function executed and that
+// the result is retrieved into a matching type.
+type ArgumentChecker struct {
+	*checkerDebug
+}
+
+// NewArgumentChecker creates an ArgumentChecker for the given config.
+func NewArgumentChecker(config ArgumentConfig) *ArgumentChecker {
+	// Build checker
+	return &ArgumentChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is an -argument-debug flag for enabling debug logs. This
+// analyzer does not have any results but does set *VarFuncs facts on
+// package-level vars.
+func (c *ArgumentChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowargs",
+		Doc:       "Analyzes activity and child workflow executions for mismatched arguments and results",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "argument-debug", c.Debug, "show argument debug output")
+	return a
+}
+
+// execution is a call executing an activity or child workflow whose function
+// is known.
+type execution struct {
+	// Either "activity" or "child workflow" followed by the function name
+	subject string
+	sig     *types.Signature
+}
+
+// Run executes this checker for the given pass.
+func (c *ArgumentChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *ArgumentChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	c.debugf("Checking arguments of package %v", pass.Pkg.Path())
+	r := p.resolver
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			callExpr, _ := n.(*ast.CallExpr)
+			if callExpr == nil {
+				return true
+			}
+			callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+			if callee == nil {
+				return true
+			}
+			if exec := c.execution(pass, r, callExpr); exec != nil {
+				args := callExpr.Args[executeCalls[callee.FullName()].fnIndex+1:]
+				// Arguments already in a slice cannot be checked
+				if callExpr.Ellipsis == token.NoPos {
+					c.checkArgs(pass, callExpr, exec, args)
+				}
+			} else if futureGetFuncs[callee.FullName()] && len(callExpr.Args) == 2 {
+				if sel, _ := callExpr.Fun.(*ast.SelectorExpr); sel != nil {
+					if exec := c.futureExecution(pass, r, sel.X); exec != nil {
+						c.checkGet(pass, callExpr.Args[1], exec)
+					}
+				}
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// execution returns the execution for the given call or nil if it is not an
+// execution or the function executed cannot be determined.
+func (c *ArgumentChecker) execution(pass *analysis.Pass, r *rootResolver, callExpr *ast.CallExpr) *execution {
+	callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+	if callee == nil {
+		return nil
+	}
+	call, ok := executeCalls[callee.FullName()]
+	if !ok || call.fnIndex >= len(callExpr.Args) {
+		return nil
+	}
+	// Use the signature of the expression if it's a function, otherwise the
+	// resolved function if there is exactly one
+	fnExpr := callExpr.Args[call.fnIndex]
+	fns := r.resolve(callExpr.Pos(), fnExpr)
+	var sig *types.Signature
+	if t := pass.TypesInfo.TypeOf(fnExpr); t != nil {
+		sig, _ = t.Underlying().(*types.Signature)
+	}
+	if sig == nil && len(fns) == 1 {
+		sig = fns[0].signature(pass)
+	}
+	if sig == nil {
+		return nil
+	}
+	name := types.ExprString(fnExpr)
+	if len(fns) == 1 {
+		name = fns[0].name
+	}
+	return &execution{subject: call.kind + " " + name, sig: sig}
+}
+
+// futureExecution returns the execution the future expression came from or
+// nil if unknown. The future must be the execution call itself or a var only
+// assigned once from an execution call.
+func (c *ArgumentChecker) futureExecution(pass *analysis.Pass, r *rootResolver, expr ast.Expr) *execution {
+	if ident := calleeIdent(expr); ident != nil {
+		v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if v == nil || len(r.varValues[v]) != 1 {
+			return nil
+		}
+		expr = r.varValues[v][0]
+	}
+	if callExpr, _ := expr.(*ast.CallExpr); callExpr != nil {
+		return c.execution(pass, r, callExpr)
+	}
+	return nil
+}
+
+// checkArgs reports a mismatch in the number of arguments or any argument not
+// assignable to its parameter.
+func (c *ArgumentChecker) checkArgs(pass *analysis.Pass, callExpr *ast.CallExpr, exec *execution, args []ast.Expr) {
+	// Leading contexts are not given as arguments
+	var params []*types.Var
+	for i := 0; i < exec.sig.Params().Len(); i++ {
+		param := exec.sig.Params().At(i)
+		if i > 0 || !(isWorkflowContext(param.Type()) || isContext(param.Type())) {
+			params = append(params, param)
+		}
+	}
+	if exec.sig.Variadic() {
+		if len(args) < len(params)-1 {
+			pass.Reportf(callExpr.Pos(), "%v expects at least %v arguments but was given %v",
+				exec.subject, len(params)-1, len(args))
+			return
+		}
+	} else if len(args) != len(params) {
+		pass.Reportf(callExpr.Pos(), "%v expects %v arguments but was given %v", exec.subject, len(params), len(args))
+		return
+	}
+	qual := types.RelativeTo(pass.Pkg)
+	for i, arg := range args {
+		var paramType types.Type
+		if exec.sig.Variadic() && i >= len(params)-1 {
+			paramType = params[len(params)-1].Type().(*types.Slice).Elem()
+		} else {
+			paramType = params[i].Type()
+		}
+		if !argAssignable(pass, arg, paramType) {
+			pass.Reportf(arg.Pos(), "%v argument %v has type %v but parameter has type %v", exec.subject, i+1,
+				types.TypeString(pass.TypesInfo.TypeOf(arg), qual), types.TypeString(paramType, qual))
+		}
+	}
+}
+
+// argAssignable returns true if the argument can be given for the parameter
+// type. Since the arguments are given as interface{}, constants and nil are
+// recorded with their default type so they are checked by kind and value
+// instead. Otherwise the argument is checked by jsonDecodable.
+func argAssignable(pass *analysis.Pass, arg ast.Expr, paramType types.Type) bool {
+	tv := pass.TypesInfo.Types[arg]
+	if tv.IsNil() {
+		switch paramType.Underlying().(type) {
+		case *types.Pointer, *types.Slice, *types.Map, *types.Interface, *types.Chan, *types.Signature:
+			return true
+		}
+		return false
+	}
+	if tv.Value != nil {
+		argBasic, _ := tv.Type.Underlying().(*types.Basic)
+		paramBasic, _ := derefAll(paramType).Underlying().(*types.Basic)
+		if argBasic != nil && paramBasic != nil {
+			return constantDecodable(tv.Value, paramBasic)
+		}
+	}
+	return jsonDecodable(tv.Type, paramType)
+}
+
+// constantDecodable returns true if the constant encoded as JSON can be
+// decoded into the basic type.
+func constantDecodable(val constant.Value, basic *types.Basic) bool {
+	switch val.Kind() {
+	case constant.Bool:
+		return basic.Info()&types.IsBoolean != 0
+	case constant.String:
+		return basic.Info()&types.IsString != 0
+	case constant.Int, constant.Float:
+		if basic.Info()&types.IsFloat != 0 {
+			return true
+		} else if basic.Info()&types.IsInteger == 0 {
+			return false
+		}
+		// Integers cannot be decoded from fractions or unsigned from negatives
+		intVal := constant.ToInt(val)
+		return intVal.Kind() == constant.Int && (basic.Info()&types.IsUnsigned == 0 || constant.Sign(intVal) >= 0)
+	}
+	return false
+}
+
+// jsonDecodable returns true if a value of the from type encoded by the
+// default JSON data converter can be decoded into the to type. Pointers are
+// dereferenced and numbers of different sizes are compatible. Values without a
+// concrete type are assumed decodable.
+func jsonDecodable(from, to types.Type) bool {
+	from, to = derefAll(from), derefAll(to)
+	if types.AssignableTo(from, to) {
+		return true
+	} else if _, isIface := from.Underlying().(*types.Interface); isIface {
+		return true
+	}
+	switch fromType := from.Underlying().(type) {
+	case *types.Basic:
+		toType, _ := to.Underlying().(*types.Basic)
+		if toType == nil {
+			return false
+		}
+		switch {
+		case fromType.Info()&types.IsBoolean != 0:
+			return toType.Info()&types.IsBoolean != 0
+		case fromType.Info()&types.IsString != 0:
+			return toType.Info()&types.IsString != 0
+		case fromType.Info()&types.IsInteger != 0:
+			return toType.Info()&(types.IsInteger|types.IsFloat) != 0
+		case fromType.Info()&types.IsFloat != 0:
+			return toType.Info()&types.IsFloat != 0
+		}
+	case *types.Slice:
+		if toType, _ := to.Underlying().(*types.Slice); toType != nil {
+			return jsonDecodable(fromType.Elem(), toType.Elem())
+		} else if toType, _ := to.Underlying().(*types.Array); toType != nil {
+			return jsonDecodable(fromType.Elem(), toType.Elem())
+		}
+	case *types.Array:
+		if toType, _ := to.Underlying().(*types.Slice); toType != nil {
+			return jsonDecodable(fromType.Elem(), toType.Elem())
+		} else if toType, _ := to.Underlying().(*types.Array); toType != nil {
+			return jsonDecodable(fromType.Elem(), toType.Elem())
+		}
+	case *types.Map:
+		if toType, _ := to.Underlying().(*types.Map); toType != nil {
+			return jsonDecodable(fromType.Key(), toType.Key()) && jsonDecodable(fromType.Elem(), toType.Elem())
+		}
+	}
+	return false
+}
+
+// derefAll returns the type with all pointers removed.
+func derefAll(t types.Type) types.Type {
+	for {
+		ptr, _ := t.Underlying().(*types.Pointer)
+		if ptr == nil || types.Identical(ptr.Elem(), t) {
+			return t
+		}
+		t = ptr.Elem()
+	}
+}
+
+// checkGet reports if the destination of a future's Get does not match the
+// result type of what was executed.
+func (c *ArgumentChecker) checkGet(pass *analysis.Pass, dest ast.Expr, exec *execution) {
+	if pass.TypesInfo.Types[dest].IsNil() {
+		return
+	}
+	qual := types.RelativeTo(pass.Pkg)
+	destType := pass.TypesInfo.TypeOf(dest)
+	results := exec.sig.Results()
+	if results.Len() < 2 {
+		pass.Reportf(dest.Pos(), "%v has no result but Get was given %v", exec.subject, types.TypeString(destType, qual))
+		return
+	}
+	resultType := results.At(0).Type()
+	// Results without a concrete type can be decoded into anything
+	if _, isIface := resultType.Underlying().(*types.Interface); isIface {
+		return
+	}
+	ptr, _ := destType.Underlying().(*types.Pointer)
+	if ptr == nil {
+		pass.Reportf(dest.Pos(), "Get destination for %v has type %v but must be a pointer to %v", exec.subject,
+			types.TypeString(destType, qual), types.TypeString(resultType, qual))
+	} else if !jsonDecodable(resultType, ptr.Elem()) {
+		pass.Reportf(dest.Pos(), "Get destination for %v has type %v but result has type %v", exec.subject,
+			types.TypeString(destType, qual), types.TypeString(resultType, qual))
+	}
+}
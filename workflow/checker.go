@@ -2,13 +2,10 @@ package workflow
 
 import (
 	"go/ast"
-	"go/types"
-	"log"
 	"strings"
 
 	"github.com/cretz/temporal-determinist/determinism"
 	"golang.org/x/tools/go/analysis"
-	"golang.org/x/tools/go/types/typeutil"
 )
 
 // DefaultIdentRefs are additional overrides of determinism.DefaultIdentRefs for
@@ -34,15 +31,109 @@ type Config struct {
 	DeterminismDebug bool
 	// If set, the file and line/col position is present on nested errors.
 	IncludePosOnMessage bool
+	// If non-nil, enables allowlist mode on the determinism checker. See
+	// determinism.Config.Allowlist.
+	Allowlist determinism.Allowlist
+	// Module whose code is not third-party. See
+	// determinism.Config.MainModule.
+	MainModule string
+	// If non-empty, enables module boundary mode on the determinism checker. See
+	// determinism.Config.BoundaryModules.
+	BoundaryModules []string
+	// Policies for functions outside of BoundaryModules. See
+	// determinism.Config.BoundaryPolicies.
+	BoundaryPolicies determinism.BoundaryPolicies
+	// If set, exported functions whose first parameter is a workflow.Context
+	// and whose last result is an error are checked as workflows even if they
+	// are not registered in the package.
+	DiscoverBySignature bool
+	// Non-determinisms with a lower confidence than this are not reported.
+	MinConfidence determinism.Confidence
+	// If set, packages defining registered workflows are checked for forbidden
+	// imports.
+	CheckImports bool
+	// If empty, uses DefaultImportPolicy.
+	DefaultImportPolicy ImportPolicy
+	// If set, workflow and activity registrations are checked for conflicting
+	// type names on the same worker.
+	CheckRegistrations bool
+	// If set, workflow, activity, signal, and query payload types are checked
+	// for serializability.
+	CheckPayloads bool
+	// If set, arguments to executed activities and child workflows are checked
+	// against the parameters of the function executed.
+	CheckArguments bool
+	// If set, direct calls from workflows to functions registered as
+	// activities in the same package are reported as non-deterministic.
+	CheckActivityCalls bool
+	// If set, functions registered as activities are checked for use of
+	// workflow-only APIs.
+	CheckActivities bool
+	// If set, methods registered as activities are checked for writes to
+	// shared receiver state without holding a mutex.
+	CheckActivityState bool
+	// If set, activities and child workflows executed by a constant type name
+	// are checked against the names registered in the worker package and its
+	// dependencies.
+	CheckNames bool
+	// Type names registered outside of the analyzed packages that may be
+	// executed by name. Only applies if CheckNames is set.
+	ExternalNames []string
+	// If set, the context options used to execute activities and child
+	// workflows are checked for missing or invalid timeouts.
+	CheckOptions bool
+	// If set, workflow code is checked for discarded futures and unchecked
+	// errors from Get.
+	CheckFutures bool
+	// If set, callbacks given to Selector.AddReceive are checked for never
+	// receiving from the channel.
+	CheckSelectors bool
+	// If set, workflows are checked for continuing as new without draining
+	// their signal channels.
+	CheckContinueAsNew bool
+	// If set, workflows are checked for loops without a static bound that
+	// issue workflow commands but never continue as new.
+	CheckLoops bool
+	// If set, workflow cleanup code is checked for executing activities with
+	// a context that may already be cancelled.
+	CheckCleanup bool
 }
 
 // Checker checks if functions passed RegisterWorkflow are non-deterministic
 // based on the results from the checker of the adjacent determinism package.
 type Checker struct {
-	DebugfFunc          func(string, ...interface{})
-	Debug               bool
+	*checkerDebug
 	IncludePosOnMessage bool
+	DiscoverBySignature bool
+	MinConfidence       determinism.Confidence
 	Determinism         *determinism.Checker
+	CheckImports        bool
+	Imports             *ImportChecker
+	CheckRegistrations  bool
+	Registrations       *RegistrationChecker
+	CheckPayloads       bool
+	Payloads            *PayloadChecker
+	CheckArguments      bool
+	Arguments           *ArgumentChecker
+	CheckActivityCalls  bool
+	CheckActivities     bool
+	Activities          *ActivityChecker
+	CheckActivityState  bool
+	ActivityState       *ActivityStateChecker
+	CheckNames          bool
+	Names               *NameChecker
+	CheckOptions        bool
+	Options             *OptionChecker
+	CheckFutures        bool
+	Futures             *FutureChecker
+	CheckSelectors      bool
+	Selectors           *SelectorChecker
+	CheckContinueAsNew  bool
+	ContinueAsNew       *ContinueAsNewChecker
+	CheckLoops          bool
+	Loops               *LoopChecker
+	CheckCleanup        bool
+	Cleanup             *CleanupChecker
 }
 
 // NewChecker creates a Checker for the given config.
@@ -52,101 +143,231 @@ func NewChecker(config Config) *Checker {
 	if config.DefaultIdentRefs == nil {
 		config.DefaultIdentRefs = DefaultIdentRefs
 	}
-	// Default debug
-	if config.DebugfFunc == nil {
-		config.DebugfFunc = log.Printf
-	}
-	// Build checker
-	return &Checker{
-		DebugfFunc:          config.DebugfFunc,
-		Debug:               config.Debug,
+	// Build checker, sharing its debug logging with every checker it runs so
+	// the -workflow-debug flag applies to them all
+	debug := newCheckerDebug(config.DebugfFunc, config.Debug)
+	c := &Checker{
+		checkerDebug:        debug,
 		IncludePosOnMessage: config.IncludePosOnMessage,
+		DiscoverBySignature: config.DiscoverBySignature,
+		MinConfidence:       config.MinConfidence,
 		Determinism: determinism.NewChecker(determinism.Config{
 			DefaultIdentRefs: config.DefaultIdentRefs,
-			DebugfFunc:       config.DebugfFunc,
+			DebugfFunc:       debug.DebugfFunc,
 			Debug:            config.DeterminismDebug,
+			Allowlist:        config.Allowlist,
+			MainModule:       config.MainModule,
+			BoundaryModules:  config.BoundaryModules,
+			BoundaryPolicies: config.BoundaryPolicies,
 		}),
+		CheckImports:       config.CheckImports,
+		Imports:            NewImportChecker(ImportConfig{DefaultPolicy: config.DefaultImportPolicy}),
+		CheckRegistrations: config.CheckRegistrations,
+		Registrations:      NewRegistrationChecker(RegistrationConfig{}),
+		CheckPayloads:      config.CheckPayloads,
+		Payloads:           NewPayloadChecker(PayloadConfig{}),
+		CheckArguments:     config.CheckArguments,
+		Arguments:          NewArgumentChecker(ArgumentConfig{}),
+		CheckActivityCalls: config.CheckActivityCalls,
+		CheckActivities:    config.CheckActivities,
+		Activities:         NewActivityChecker(ActivityConfig{}),
+		CheckActivityState: config.CheckActivityState,
+		ActivityState:      NewActivityStateChecker(ActivityStateConfig{}),
+		CheckNames:         config.CheckNames,
+		Names:              NewNameChecker(NameConfig{ExternalNames: config.ExternalNames}),
+		CheckOptions:       config.CheckOptions,
+		Options:            NewOptionChecker(OptionConfig{}),
+		CheckFutures:       config.CheckFutures,
+		Futures:            NewFutureChecker(FutureConfig{}),
+		CheckSelectors:     config.CheckSelectors,
+		Selectors:          NewSelectorChecker(SelectorConfig{}),
+		CheckContinueAsNew: config.CheckContinueAsNew,
+		ContinueAsNew:      NewContinueAsNewChecker(ContinueAsNewConfig{}),
+		CheckLoops:         config.CheckLoops,
+		Loops:              NewLoopChecker(LoopConfig{}),
+		CheckCleanup:       config.CheckCleanup,
+		Cleanup:            NewCleanupChecker(CleanupConfig{}),
+	}
+	for _, check := range c.checks() {
+		*check.debug = debug
 	}
+	return c
 }
 
-func (c *Checker) debugf(f string, v ...interface{}) {
-	if c.Debug {
-		c.DebugfFunc(f, v...)
+// packageCheck is a checker run by Checker after the determinism check.
+type packageCheck struct {
+	enabled bool
+	debug   **checkerDebug
+	run     func(*checkedPackage) error
+}
+
+// checks returns every checker run by Checker in the order they are run.
+func (c *Checker) checks() []packageCheck {
+	return []packageCheck{
+		{c.CheckImports, &c.Imports.checkerDebug, c.Imports.run},
+		{c.CheckRegistrations, &c.Registrations.checkerDebug, c.Registrations.run},
+		{c.CheckPayloads, &c.Payloads.checkerDebug, c.Payloads.run},
+		{c.CheckArguments, &c.Arguments.checkerDebug, c.Arguments.run},
+		{c.CheckActivities, &c.Activities.checkerDebug, func(p *checkedPackage) error {
+			return c.Activities.run(p, c.IncludePosOnMessage)
+		}},
+		{c.CheckActivityState, &c.ActivityState.checkerDebug, c.ActivityState.run},
+		{c.CheckNames, &c.Names.checkerDebug, c.Names.run},
+		{c.CheckOptions, &c.Options.checkerDebug, c.Options.run},
+		{c.CheckFutures, &c.Futures.checkerDebug, c.Futures.run},
+		{c.CheckSelectors, &c.Selectors.checkerDebug, c.Selectors.run},
+		{c.CheckContinueAsNew, &c.ContinueAsNew.checkerDebug, c.ContinueAsNew.run},
+		{c.CheckLoops, &c.Loops.checkerDebug, c.Loops.run},
+		{c.CheckCleanup, &c.Cleanup.checkerDebug, c.Cleanup.run},
 	}
 }
 
 // NewAnalyzer creates a Go analysis analyzer that can be used in existing
 // tools. There is a -set-decl flag for adding ident refs overrides, a
 // -workflow-debug flag for enabling debug logs, a -determinism-debug flag for
-// enabling determinism debug logs, and a -show-pos flag for showing position on
-// nested errors. This analyzer does not have any results but does set the same
-// facts as the determinism analyzer (*determinism.NonDeterminisms).
+// enabling determinism debug logs, a -show-pos flag for showing position on
+// nested errors, and a flag for enabling each other check as listed in the
+// README. This analyzer does not have any results but does set the same facts
+// as the determinism analyzer (*determinism.NonDeterminisms) and each check.
 func (c *Checker) NewAnalyzer() *analysis.Analyzer {
 	a := &analysis.Analyzer{
-		Name:      "workflow",
-		Doc:       "Analyzes all RegisterWorkflow functions for non-determinism",
-		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
-		FactTypes: []analysis.Fact{&determinism.NonDeterminisms{}},
+		Name: "workflow",
+		Doc:  "Analyzes all RegisterWorkflow functions for non-determinism",
+		Run:  func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{
+			&determinism.NonDeterminisms{},
+			&ImportChains{},
+			&Registrations{},
+			&Callees{},
+			&RegisteredActivities{},
+			&WorkflowUses{},
+			&SharedWrites{},
+			&Names{},
+			&NamedInvocations{},
+			&ConsumedChannels{},
+			&LostSignals{},
+			&DrainedChannels{},
+			&UnboundedLoops{},
+			&UnsafeCleanups{},
+			&VarFuncs{},
+		},
 	}
 	// Set flags
 	a.Flags.Var(determinism.NewIdentRefsFlag(c.Determinism.IdentRefs), "set-decl",
 		"qualified function/var to include/exclude, overriding the default (append '=false' to exclude)")
+	a.Flags.Var(determinism.NewAllowlistFlag(&c.Determinism.Allowlist), "allow",
+		"qualified function or package path (optionally ending in '/...') to allow calls to, enabling allowlist mode "+
+			"(append '=false' to disallow)")
+	a.Flags.Var(determinism.NewBoundaryMainModuleFlag(&c.Determinism.BoundaryModules), "boundary",
+		"walk functions of the main module only, enabling module boundary mode")
+	a.Flags.Var(determinism.NewBoundaryModulesFlag(&c.Determinism.BoundaryModules), "boundary-module",
+		"module path to walk functions of instead of or in addition to the main module, enabling module boundary mode")
+	a.Flags.Var(determinism.NewBoundaryPoliciesFlag(c.Determinism.BoundaryPolicies), "boundary-policy",
+		"module path (or 'std') and policy of 'rules', 'trust', or 'distrust' in MODULE=POLICY form for calls "+
+			"outside of the boundary")
 	a.Flags.BoolVar(&c.Debug, "workflow-debug", c.Debug, "show workflow debug output")
 	a.Flags.BoolVar(&c.Determinism.Debug, "determism-debug", c.Determinism.Debug, "show determinism debug output")
 	a.Flags.BoolVar(&c.IncludePosOnMessage, "show-pos", c.IncludePosOnMessage,
 		"show file positions on determinism messages")
+	a.Flags.BoolVar(&c.DiscoverBySignature, "discover-by-signature", c.DiscoverBySignature,
+		"check exported functions with a workflow.Context first parameter and error last result as workflows")
+	a.Flags.Var(determinism.NewConfidenceFlag(&c.MinConfidence), "min-confidence",
+		"minimum confidence of 'low', 'medium', or 'high' for non-determinisms to be reported")
+	a.Flags.BoolVar(&c.CheckImports, "check-imports", c.CheckImports,
+		"check packages defining workflows for forbidden imports")
+	a.Flags.Var(NewImportPolicyFlag(c.Imports.Policy), "forbid-import",
+		"package path (optionally ending in '/...') workflow packages may not import, overriding the default "+
+			"(append '=false' to allow)")
+	a.Flags.BoolVar(&c.CheckRegistrations, "check-registrations", c.CheckRegistrations,
+		"check workflow and activity registrations for conflicting type names")
+	a.Flags.BoolVar(&c.CheckPayloads, "check-payloads", c.CheckPayloads,
+		"check workflow, activity, signal, and query payload types for serializability")
+	a.Flags.BoolVar(&c.CheckArguments, "check-args", c.CheckArguments,
+		"check arguments and result destinations of executed activities and child workflows")
+	a.Flags.BoolVar(&c.CheckActivityCalls, "check-activity-calls", c.CheckActivityCalls,
+		"check workflows for direct calls to functions registered as activities")
+	a.Flags.BoolVar(&c.CheckActivities, "check-activities", c.CheckActivities,
+		"check functions registered as activities for use of workflow-only APIs")
+	a.Flags.BoolVar(&c.CheckActivityState, "check-activity-state", c.CheckActivityState,
+		"check methods registered as activities for unprotected writes to shared receiver state")
+	a.Flags.BoolVar(&c.CheckNames, "check-names", c.CheckNames,
+		"check activities and child workflows executed by name against registered type names")
+	a.Flags.Var(NewExternalNamesFlag(&c.Names.ExternalNames), "external-name",
+		"workflow or activity type name registered outside of the analyzed packages")
+	a.Flags.BoolVar(&c.CheckOptions, "check-options", c.CheckOptions,
+		"check the context options of executed activities and child workflows for missing or invalid timeouts")
+	a.Flags.BoolVar(&c.CheckFutures, "check-futures", c.CheckFutures,
+		"check workflow code for discarded futures and unchecked errors from Get")
+	a.Flags.BoolVar(&c.CheckSelectors, "check-selectors", c.CheckSelectors,
+		"check selector receive callbacks for never receiving from the channel")
+	a.Flags.BoolVar(&c.CheckContinueAsNew, "check-continue-as-new", c.CheckContinueAsNew,
+		"check workflows for continuing as new without draining signal channels")
+	a.Flags.BoolVar(&c.CheckLoops, "check-loops", c.CheckLoops,
+		"check workflows for loops without a static bound that never continue as new")
+	a.Flags.BoolVar(&c.CheckCleanup, "check-cleanup", c.CheckCleanup,
+		"check workflow cleanup code for executing activities with a possibly cancelled context")
 	return a
 }
 
 // Run executes this checker for the given pass.
 func (c *Checker) Run(pass *analysis.Pass) error {
 	// Run determinism pass
-	if _, err := c.Determinism.Run(pass); err != nil {
+	detRes, err := c.Determinism.Run(pass)
+	if err != nil {
 		return err
 	}
 	c.debugf("Checking package %v", pass.Pkg.Path())
-	// Check every register workflow invocation
-	for _, file := range pass.Files {
-		ast.Inspect(file, func(n ast.Node) bool {
-			// Only handle calls
-			callExpr, _ := n.(*ast.CallExpr)
-			if callExpr == nil {
-				return true
-			}
-			// Callee needs to be workflow registry
-			callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
-			const regName = "(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterWorkflow"
-			const regOptName = "(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterWorkflowWithOptions"
-			if callee == nil || len(callExpr.Args) == 0 || (callee.FullName() != regName && callee.FullName() != regOptName) {
-				return true
-			}
-			// First param should be a function ident or a selector with ident as
-			// function
-			var fn *types.Func
-			switch arg := callExpr.Args[0].(type) {
-			case *ast.Ident:
-				fn, _ = pass.TypesInfo.ObjectOf(arg).(*types.Func)
-			case *ast.SelectorExpr:
-				fn, _ = pass.TypesInfo.ObjectOf(arg.Sel).(*types.Func)
+	// Check every registered or declared workflow
+	p := newCheckedPackage(pass, c.DiscoverBySignature)
+	for _, expr := range p.unresolved {
+		pass.Reportf(expr.Pos(), "unrecognized function reference format")
+	}
+	// Collect activities and export call graph if checking activity calls
+	var activities RegisteredActivities
+	calleeFinder := newCalleeFinder(p)
+	if c.CheckActivityCalls {
+		exportCallees(p, calleeFinder)
+		activities = registeredActivities(p)
+	}
+	for _, root := range p.roots {
+		c.debugf("Checking workflow function %v", root.subject())
+		// Interceptor methods are not workflow functions themselves
+		if sig := root.signature(pass); sig != nil && !root.interceptor {
+			for _, problem := range signatureProblems(sig) {
+				pass.Reportf(root.pos, "%v has invalid workflow signature: %v", root.subject(), problem)
 			}
-			// Report if couldn't get type
-			if fn == nil {
-				pass.Reportf(callExpr.Args[0].Pos(), "unrecognized function reference format")
-				return true
+		}
+		// If there are any non-determinisms, we need to mark the diagnostics
+		var reasons determinism.NonDeterminisms
+		p.rootFact(root, &reasons, func(lit *ast.FuncLit) { reasons = c.Determinism.NodeNonDeterminisms(pass, detRes, lit) })
+		if len(activities) > 0 {
+			// Limit capacity so the fact is not appended to
+			reasons = append(reasons[:len(reasons):len(reasons)], activityCallReasons(p, calleeFinder, root, activities)...)
+		}
+		// One report per reason with the confidence as the category
+		for _, reason := range reasons {
+			conf := c.Determinism.Confidence(reason)
+			if conf < c.MinConfidence {
+				c.debugf("Skipping %v reason %v with confidence %v", root.subject(), reason, conf)
+				continue
 			}
-			c.debugf("Checking workflow function %v", fn.FullName())
-			// If there are any non-determinisms, we need to mark the diagnostics
-			var reasons determinism.NonDeterminisms
-			if pass.ImportObjectFact(fn, &reasons) && len(reasons) > 0 {
-				// One report per reason
-				for _, reason := range reasons {
-					lines := determinism.NonDeterminisms{reason}.AppendChildReasonLines(
-						fn.FullName(), nil, 0, c.IncludePosOnMessage)
-					pass.Report(analysis.Diagnostic{Pos: callExpr.Pos(), Message: strings.Join(lines, "\n")})
-				}
+			lines := determinism.NonDeterminisms{reason}.AppendChildReasonLines(
+				root.subject(), nil, 0, c.IncludePosOnMessage)
+			lines[0] += " (confidence: " + conf.String() + ")"
+			pass.Report(analysis.Diagnostic{
+				Pos:      root.pos,
+				Category: conf.String(),
+				Message:  strings.Join(lines, "\n"),
+			})
+		}
+	}
+	// Run every other enabled check
+	for _, check := range c.checks() {
+		if check.enabled {
+			if err := check.run(p); err != nil {
+				return err
 			}
-			return true
-		})
+		}
 	}
 	return nil
 }
@@ -0,0 +1,380 @@
+package workflow
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/cretz/temporal-determinist/determinism"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// UnsafeCleanups is the object fact of activities executed by cleanup code in
+// a function with a context that may already be cancelled.
+type UnsafeCleanups []*UnsafeCleanup
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*UnsafeCleanups) AFact() {}
+
+// String returns all cleanups as a comma-delimited string.
+func (u *UnsafeCleanups) String() string {
+	if u == nil {
+		return "<none>"
+	}
+	strs := make([]string, len(*u))
+	for i, cleanup := range *u {
+		strs[i] = cleanup.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// UnsafeCleanup is an execution of an activity in cleanup code with a context
+// not derived from a disconnected context.
+type UnsafeCleanup struct {
+	// Position of the execution
+	Pos token.Position
+	// True if in a deferred call, false if in a cancellation branch
+	Deferred bool
+	// The function executing the activity
+	Func *types.Func
+}
+
+// String returns the cleanup without the position.
+func (u *UnsafeCleanup) String() string {
+	where := "cancellation branch"
+	if u.Deferred {
+		where = "deferred call"
+	}
+	return where + " calls " + u.Func.FullName() + " with a context not from workflow.NewDisconnectedContext"
+}
+
+// cleanupExecuteFuncs are the qualified names of functions that execute
+// activities with the context given as the first argument.
+var cleanupExecuteFuncs = map[string]bool{
+	"go.temporal.io/sdk/workflow.ExecuteActivity":      true,
+	"go.temporal.io/sdk/internal.ExecuteActivity":      true,
+	"go.temporal.io/sdk/workflow.ExecuteLocalActivity": true,
+	"go.temporal.io/sdk/internal.ExecuteLocalActivity": true,
+}
+
+// disconnectedContextFuncs are the qualified names of functions returning a
+// context that is not cancelled with its parent as the first result.
+var disconnectedContextFuncs = map[string]bool{
+	"go.temporal.io/sdk/workflow.NewDisconnectedContext": true,
+	"go.temporal.io/sdk/internal.NewDisconnectedContext": true,
+}
+
+// cancellationCheckFuncs are the qualified names of functions that check
+// whether a workflow has been cancelled.
+var cancellationCheckFuncs = map[string]bool{
+	"(go.temporal.io/sdk/workflow.Context).Err":   true,
+	"(go.temporal.io/sdk/internal.Context).Err":   true,
+	"go.temporal.io/sdk/temporal.IsCanceledError": true,
+	"go.temporal.io/sdk/internal.IsCanceledError": true,
+}
+
+// CleanupConfig is config for NewCleanupChecker.
+type CleanupConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// CleanupChecker checks that activities executed by workflow cleanup code use
+// a disconnected context, since the workflow context may already be
+// cancelled.
+type CleanupChecker struct {
+	*checkerDebug
+}
+
+// NewCleanupChecker creates a CleanupChecker for the given config.
+func NewCleanupChecker(config CleanupConfig) *CleanupChecker {
+	// Build checker
+	return &CleanupChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is a -cleanup-debug flag for enabling debug logs. This analyzer
+// does not have any results but does set *UnsafeCleanups facts on functions and
+// *VarFuncs facts on package-level vars.
+func (c *CleanupChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowcleanup",
+		Doc:       "Analyzes workflow cleanup code for executing activities with a possibly cancelled context",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&UnsafeCleanups{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "cleanup-debug", c.Debug, "show cleanup debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *CleanupChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *CleanupChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	if !callGraphPackage(pass.Pkg) {
+		return nil
+	}
+	c.debugf("Checking cleanup of package %v", pass.Pkg.Path())
+	f := newCleanupFinder(p)
+	// Set facts for every function with unsafe cleanups
+	for fn, funcDecl := range f.resolver.funcDecls {
+		if funcDecl.Body == nil {
+			continue
+		}
+		if cleanups := f.unsafeCleanups(funcDecl.Body); len(cleanups) > 0 {
+			c.debugf("Marking %v as having unsafe cleanups", fn.FullName())
+			pass.ExportObjectFact(fn, &cleanups)
+		}
+	}
+	// Report each for every workflow
+	for _, root := range p.roots {
+		if root.interceptor {
+			continue
+		}
+		var cleanups UnsafeCleanups
+		p.rootFact(root, &cleanups, func(lit *ast.FuncLit) { cleanups = f.unsafeCleanups(lit.Body) })
+		for _, cleanup := range cleanups {
+			pass.Reportf(root.pos, "%v may skip cleanup when cancelled, reason: %v at %v",
+				root.subject(), cleanup, determinism.RelativePosition(cleanup.Pos))
+		}
+	}
+	return nil
+}
+
+type cleanupFinder struct {
+	pass     *analysis.Pass
+	resolver *rootResolver
+	// Assignments to vars
+	assignments map[*types.Var][]*contextAssignment
+}
+
+// contextAssignment is an assignment to a var that may hold a context.
+type contextAssignment struct {
+	// End of the assignment statement, after which the var has the value
+	end token.Pos
+	// The value assigned, nil if not known
+	value ast.Expr
+	// Whether the value is the context from a disconnected context call
+	disconnected bool
+}
+
+func newCleanupFinder(p *checkedPackage) *cleanupFinder {
+	pass := p.pass
+	f := &cleanupFinder{
+		pass:        pass,
+		resolver:    p.resolver,
+		assignments: map[*types.Var][]*contextAssignment{},
+	}
+	add := func(ident *ast.Ident, assignment *contextAssignment) {
+		if v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var); v != nil {
+			f.assignments[v] = append(f.assignments[v], assignment)
+		}
+	}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			var lhs []*ast.Ident
+			var rhs []ast.Expr
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for _, expr := range n.Lhs {
+					ident, _ := expr.(*ast.Ident)
+					lhs = append(lhs, ident)
+				}
+				rhs = n.Rhs
+			case *ast.ValueSpec:
+				lhs, rhs = n.Names, n.Values
+			default:
+				return true
+			}
+			disconnected := false
+			if len(rhs) == 1 {
+				if callExpr, _ := rhs[0].(*ast.CallExpr); callExpr != nil {
+					callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+					disconnected = callee != nil && disconnectedContextFuncs[callee.FullName()]
+				}
+			}
+			for i, ident := range lhs {
+				if ident == nil {
+					continue
+				}
+				assignment := &contextAssignment{end: n.End()}
+				if len(lhs) == len(rhs) {
+					assignment.value = rhs[i]
+				} else {
+					// Only the first result of a disconnected context call
+					assignment.disconnected = disconnected && i == 0
+				}
+				add(ident, assignment)
+			}
+			return true
+		})
+	}
+	return f
+}
+
+// unsafeCleanups returns the activities executed in deferred calls and
+// cancellation branches of the given node with a context not derived from a
+// disconnected context. Function literals are not included unless deferred.
+func (f *cleanupFinder) unsafeCleanups(node ast.Node) (cleanups UnsafeCleanups) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			var region ast.Node = n.Call
+			if lit, _ := n.Call.Fun.(*ast.FuncLit); lit != nil {
+				region = lit.Body
+			}
+			cleanups = append(cleanups, f.regionCleanups(region, true)...)
+			return false
+		case *ast.IfStmt:
+			// The branch taken when cancelled is already cleanup
+			whenTrue, whenFalse := f.cancelledWhen(n.Cond)
+			if !whenTrue && !whenFalse {
+				return true
+			}
+			if n.Init != nil {
+				cleanups = append(cleanups, f.unsafeCleanups(n.Init)...)
+			}
+			if whenTrue {
+				cleanups = append(cleanups, f.regionCleanups(n.Body, false)...)
+			} else {
+				cleanups = append(cleanups, f.unsafeCleanups(n.Body)...)
+			}
+			if n.Else != nil && whenFalse {
+				cleanups = append(cleanups, f.regionCleanups(n.Else, false)...)
+			} else if n.Else != nil {
+				cleanups = append(cleanups, f.unsafeCleanups(n.Else)...)
+			}
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// regionCleanups returns the activities executed in the given cleanup region
+// with a context not derived from a disconnected context.
+func (f *cleanupFinder) regionCleanups(region ast.Node, deferred bool) (cleanups UnsafeCleanups) {
+	ast.Inspect(region, func(n ast.Node) bool {
+		callExpr, _ := n.(*ast.CallExpr)
+		if callExpr == nil || len(callExpr.Args) == 0 {
+			return true
+		}
+		callee, _ := typeutil.Callee(f.pass.TypesInfo, callExpr).(*types.Func)
+		if callee != nil && cleanupExecuteFuncs[callee.FullName()] && !f.disconnectedContext(callExpr.Args[0]) {
+			cleanups = append(cleanups, &UnsafeCleanup{
+				Pos:      f.pass.Fset.Position(callExpr.Pos()),
+				Deferred: deferred,
+				Func:     callee,
+			})
+		}
+		return true
+	})
+	return
+}
+
+// cancelledWhen returns whether the workflow is known to be cancelled when the
+// condition is true and when it is false. A cancellation check is calling
+// temporal.IsCanceledError or comparing Err on a workflow context against nil,
+// and negation and the && and || operators are followed.
+func (f *cleanupFinder) cancelledWhen(cond ast.Expr) (whenTrue, whenFalse bool) {
+	switch cond := cond.(type) {
+	case *ast.ParenExpr:
+		return f.cancelledWhen(cond.X)
+	case *ast.UnaryExpr:
+		if cond.Op == token.NOT {
+			whenTrue, whenFalse = f.cancelledWhen(cond.X)
+			return whenFalse, whenTrue
+		}
+	case *ast.BinaryExpr:
+		switch cond.Op {
+		case token.LAND:
+			xTrue, xFalse := f.cancelledWhen(cond.X)
+			yTrue, yFalse := f.cancelledWhen(cond.Y)
+			return xTrue || yTrue, xFalse && yFalse
+		case token.LOR:
+			xTrue, xFalse := f.cancelledWhen(cond.X)
+			yTrue, yFalse := f.cancelledWhen(cond.Y)
+			return xTrue && yTrue, xFalse || yFalse
+		case token.EQL, token.NEQ:
+			checked := cond.X
+			if f.pass.TypesInfo.Types[checked].IsNil() {
+				checked = cond.Y
+			} else if !f.pass.TypesInfo.Types[cond.Y].IsNil() {
+				return false, false
+			}
+			if !f.cancellationCheck(checked) {
+				return false, false
+			}
+			return cond.Op == token.NEQ, cond.Op == token.EQL
+		}
+	case *ast.CallExpr:
+		return f.cancellationCheck(cond), false
+	}
+	return false, false
+}
+
+// cancellationCheck returns true if the expression is a call that checks
+// whether the workflow has been cancelled.
+func (f *cleanupFinder) cancellationCheck(expr ast.Expr) bool {
+	callExpr, _ := expr.(*ast.CallExpr)
+	if callExpr == nil {
+		return false
+	}
+	callee, _ := typeutil.Callee(f.pass.TypesInfo, callExpr).(*types.Func)
+	return callee != nil && cancellationCheckFuncs[callee.FullName()]
+}
+
+// disconnectedContext returns true if the context expression is derived from
+// a disconnected context through vars or SDK functions deriving contexts. A
+// var has the value of its last assignment before the expression.
+func (f *cleanupFinder) disconnectedContext(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return f.disconnectedContext(expr.X)
+	case *ast.Ident:
+		v, _ := f.pass.TypesInfo.ObjectOf(expr).(*types.Var)
+		if v == nil {
+			return false
+		}
+		// Each assignment followed ends before the expression, so this always
+		// terminates
+		var last *contextAssignment
+		for _, assignment := range f.assignments[v] {
+			if assignment.end <= expr.Pos() && (last == nil || assignment.end > last.end) {
+				last = assignment
+			}
+		}
+		if last == nil {
+			return false
+		} else if last.disconnected {
+			return true
+		} else if last.value == nil {
+			return false
+		}
+		return f.disconnectedContext(last.value)
+	case *ast.CallExpr:
+		callee, _ := typeutil.Callee(f.pass.TypesInfo, expr).(*types.Func)
+		if callee == nil || callee.Pkg() == nil || len(expr.Args) == 0 ||
+			(callee.Pkg().Path() != "go.temporal.io/sdk/workflow" && callee.Pkg().Path() != "go.temporal.io/sdk/internal") {
+			return false
+		}
+		sig, _ := callee.Type().(*types.Signature)
+		if sig != nil && sig.Params().Len() > 0 && isWorkflowContext(sig.Params().At(0).Type()) &&
+			sig.Results().Len() > 0 && isWorkflowContext(sig.Results().At(0).Type()) {
+			return f.disconnectedContext(expr.Args[0])
+		}
+	}
+	return false
+}
@@ -0,0 +1,404 @@
+package workflow
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cretz/temporal-determinist/determinism"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// LostSignals is the object fact of returns of a continue-as-new error from a
+// function that do not first drain the signal channels the function obtained.
+type LostSignals []*LostSignal
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*LostSignals) AFact() {}
+
+// String returns all lost signals as a comma-delimited string.
+func (l *LostSignals) String() string {
+	if l == nil {
+		return "<none>"
+	}
+	strs := make([]string, len(*l))
+	for i, lost := range *l {
+		strs[i] = lost.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// LostSignal is a return of a continue-as-new error without draining a signal
+// channel.
+type LostSignal struct {
+	// Position of the return
+	Pos token.Position
+	// Name of the channel var
+	Channel string
+	// Name of the signal if constant
+	Signal string
+}
+
+// String returns the lost signal without the position.
+func (l *LostSignal) String() string {
+	str := "continues as new without draining signal channel " + l.Channel
+	if l.Signal != "" {
+		str += " (signal " + strconv.Quote(l.Signal) + ")"
+	}
+	return str
+}
+
+// DrainedChannels is the object fact of the receive channel params of a
+// function, by index, that are drained with ReceiveAsync or
+// ReceiveAsyncWithMoreFlag, directly or by passing them to another function
+// that drains them. Params not drained are not present.
+type DrainedChannels map[int]bool
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*DrainedChannels) AFact() {}
+
+// String returns every drained param, sorted by index.
+func (d *DrainedChannels) String() string {
+	if d == nil {
+		return "<none>"
+	}
+	strs := make([]string, 0, len(*d))
+	for index := range *d {
+		strs = append(strs, fmt.Sprintf("param %v drained", index))
+	}
+	sort.Strings(strs)
+	return strings.Join(strs, ", ")
+}
+
+// getSignalChannelFuncs are the qualified names of functions that return a
+// signal channel.
+var getSignalChannelFuncs = map[string]bool{
+	"go.temporal.io/sdk/workflow.GetSignalChannel": true,
+	"go.temporal.io/sdk/internal.GetSignalChannel": true,
+}
+
+// continueAsNewErrorFuncs are the qualified names of functions that return a
+// continue-as-new error.
+var continueAsNewErrorFuncs = map[string]bool{
+	"go.temporal.io/sdk/workflow.NewContinueAsNewError": true,
+	"go.temporal.io/sdk/internal.NewContinueAsNewError": true,
+}
+
+// channelDrainMethods are the names of the methods that receive from a channel
+// without blocking.
+var channelDrainMethods = map[string]bool{
+	"ReceiveAsync":             true,
+	"ReceiveAsyncWithMoreFlag": true,
+}
+
+// ContinueAsNewConfig is config for NewContinueAsNewChecker.
+type ContinueAsNewConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// ContinueAsNewChecker checks that workflows drain their signal channels
+// before continuing as new, since signals still buffered are dropped.
+type ContinueAsNewChecker struct {
+	*checkerDebug
+}
+
+// NewContinueAsNewChecker creates a ContinueAsNewChecker for the given config.
+func NewContinueAsNewChecker(config ContinueAsNewConfig) *ContinueAsNewChecker {
+	// Build checker
+	return &ContinueAsNewChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is a -continue-as-new-debug flag for enabling debug logs. This
+// analyzer does not have any results but does set *LostSignals and
+// *DrainedChannels facts on functions and *VarFuncs facts on package-level
+// vars.
+func (c *ContinueAsNewChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowcontinueasnew",
+		Doc:       "Analyzes workflows for continuing as new without draining signal channels",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&LostSignals{}, &DrainedChannels{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "continue-as-new-debug", c.Debug, "show continue-as-new debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *ContinueAsNewChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *ContinueAsNewChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	if !callGraphPackage(pass.Pkg) {
+		return nil
+	}
+	c.debugf("Checking continue-as-new of package %v", pass.Pkg.Path())
+	f := &signalDrainFinder{
+		pass:      pass,
+		resolver:  p.resolver,
+		funcDecls: p.resolver.funcDecls,
+		results:   map[*types.Func]DrainedChannels{},
+		walking:   map[*types.Func]bool{},
+	}
+	// Set facts for every function draining channel params and every function
+	// losing signals
+	for fn := range f.funcDecls {
+		f.funcDrains(fn)
+	}
+	for fn, funcDecl := range f.funcDecls {
+		if funcDecl.Body == nil {
+			continue
+		}
+		if lost := f.lostSignals(funcDecl.Body); len(lost) > 0 {
+			c.debugf("Marking %v as losing signals", fn.FullName())
+			pass.ExportObjectFact(fn, &lost)
+		}
+	}
+	// Report each for every workflow
+	for _, root := range p.roots {
+		if root.interceptor {
+			continue
+		}
+		var lost LostSignals
+		p.rootFact(root, &lost, func(lit *ast.FuncLit) { lost = f.lostSignals(lit.Body) })
+		for _, l := range lost {
+			pass.Reportf(root.pos, "%v may lose signals, reason: %v at %v", root.subject(), l, determinism.RelativePosition(l.Pos))
+		}
+	}
+	return nil
+}
+
+type signalDrainFinder struct {
+	pass      *analysis.Pass
+	resolver  *rootResolver
+	funcDecls map[*types.Func]*ast.FuncDecl
+	// Drained channels of functions in this package already found
+	results map[*types.Func]DrainedChannels
+	// Functions currently being walked to prevent recursion
+	walking map[*types.Func]bool
+}
+
+// funcDrains returns the drained receive channel params of the given function
+// in this package, setting the fact if there are any.
+func (f *signalDrainFinder) funcDrains(fn *types.Func) DrainedChannels {
+	if drains, ok := f.results[fn]; ok {
+		return drains
+	} else if f.walking[fn] || f.funcDecls[fn] == nil || f.funcDecls[fn].Body == nil {
+		return nil
+	}
+	f.walking[fn] = true
+	defer delete(f.walking, fn)
+	funcDecl := f.funcDecls[fn]
+	drains := DrainedChannels{}
+	for index, v := range paramVars(f.pass, funcDecl.Type) {
+		if v != nil && isReceiveChannel(v.Type()) && len(f.varDrains(funcDecl.Body, v)) > 0 {
+			drains[index] = true
+		}
+	}
+	f.results[fn] = drains
+	if len(drains) > 0 {
+		f.pass.ExportObjectFact(fn, &drains)
+	}
+	return drains
+}
+
+// paramDrained returns true if the param at the given index of the function is
+// known to be drained.
+func (f *signalDrainFinder) paramDrained(fn *types.Func, index int) bool {
+	var drains DrainedChannels
+	if fn.Pkg() == f.pass.Pkg {
+		drains = f.funcDrains(fn)
+	} else {
+		f.pass.ImportObjectFact(fn, &drains)
+	}
+	return drains[index]
+}
+
+// varDrains returns the calls in the given node that drain the channel var,
+// either calling a drain method on it or passing it to a function known to
+// drain it. Other uses, like adding it to a selector, are not drains.
+func (f *signalDrainFinder) varDrains(node ast.Node, v *types.Var) (drains []*ast.CallExpr) {
+	isVar := func(expr ast.Expr) bool {
+		ident := calleeIdent(expr)
+		return ident != nil && f.pass.TypesInfo.Uses[ident] == v
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		callExpr, _ := n.(*ast.CallExpr)
+		if callExpr == nil {
+			return true
+		}
+		if sel, _ := callExpr.Fun.(*ast.SelectorExpr); sel != nil && channelDrainMethods[sel.Sel.Name] && isVar(sel.X) {
+			drains = append(drains, callExpr)
+			return true
+		}
+		fn := typeutil.StaticCallee(f.pass.TypesInfo, callExpr)
+		if fn == nil || callExpr.Ellipsis.IsValid() {
+			return true
+		}
+		for index, arg := range callExpr.Args {
+			if isVar(arg) && f.paramDrained(fn, index) {
+				drains = append(drains, callExpr)
+				break
+			}
+		}
+		return true
+	})
+	return
+}
+
+// lostSignals returns the signal channels not drained before each return of a
+// continue-as-new error in the given body.
+func (f *signalDrainFinder) lostSignals(body *ast.BlockStmt) (lost LostSignals) {
+	pass := f.pass
+	// Collect signal channel vars in order and the returns of continue-as-new
+	// errors not in nested functions
+	var channels []*types.Var
+	signals := map[*types.Var]string{}
+	var returns []*ast.ReturnStmt
+	addChannel := func(lhs ast.Expr, rhs ast.Expr) {
+		ident, _ := lhs.(*ast.Ident)
+		callExpr, _ := rhs.(*ast.CallExpr)
+		if ident == nil || callExpr == nil {
+			return
+		}
+		callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+		v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if callee == nil || v == nil || !getSignalChannelFuncs[callee.FullName()] {
+			return
+		}
+		if _, ok := signals[v]; !ok {
+			channels = append(channels, v)
+		}
+		signals[v] = ""
+		if len(callExpr.Args) == 2 {
+			if val := pass.TypesInfo.Types[callExpr.Args[1]].Value; val != nil && val.Kind() == constant.String {
+				signals[v] = constant.StringVal(val)
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					addChannel(lhs, n.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) == len(n.Values) {
+				for i, name := range n.Names {
+					addChannel(name, n.Values[i])
+				}
+			}
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				if isContinueAsNewError(pass, f.resolver, result) {
+					returns = append(returns, n)
+					break
+				}
+			}
+		}
+		return true
+	})
+	if len(channels) == 0 || len(returns) == 0 {
+		return nil
+	}
+	drains := map[*types.Var][]*ast.CallExpr{}
+	for _, ch := range channels {
+		drains[ch] = f.varDrains(body, ch)
+	}
+	file := enclosingFile(pass, body)
+	for _, ret := range returns {
+		for _, ch := range channels {
+			drained := false
+			for _, drain := range drains[ch] {
+				if drainDominates(file, drain, ret) {
+					drained = true
+					break
+				}
+			}
+			if !drained {
+				lost = append(lost, &LostSignal{Pos: pass.Fset.Position(ret.Pos()), Channel: ch.Name(), Signal: signals[ch]})
+			}
+		}
+	}
+	return
+}
+
+// isContinueAsNewError returns true if the expression is a call creating a
+// continue-as-new error or a var only assigned from such calls.
+func isContinueAsNewError(pass *analysis.Pass, r *rootResolver, expr ast.Expr) bool {
+	values := []ast.Expr{expr}
+	if ident := calleeIdent(expr); ident != nil {
+		v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if v == nil {
+			return false
+		}
+		values = r.varValues[v]
+	}
+	for _, value := range values {
+		callExpr, _ := value.(*ast.CallExpr)
+		if callExpr == nil {
+			return false
+		}
+		callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+		if callee == nil || !continueAsNewErrorFuncs[callee.FullName()] {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+// enclosingFile returns the file containing the given node.
+func enclosingFile(pass *analysis.Pass, node ast.Node) *ast.File {
+	for _, file := range pass.Files {
+		if file.Pos() <= node.Pos() && node.End() <= file.End() {
+			return file
+		}
+	}
+	return nil
+}
+
+// drainDominates returns true if the drain call always runs before the return.
+// This is when the drain is before the return and is not in a branch of an if
+// or switch or in a function literal that does not also contain the return.
+// Loops are assumed to run.
+func drainDominates(file *ast.File, drain *ast.CallExpr, ret *ast.ReturnStmt) bool {
+	if file == nil || drain.End() > ret.Pos() {
+		return false
+	}
+	path, _ := astutil.PathEnclosingInterval(file, drain.Pos(), drain.End())
+	for i, n := range path {
+		if n.Pos() <= ret.Pos() && ret.End() <= n.End() {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit, *ast.CaseClause, *ast.CommClause:
+			return false
+		case *ast.BlockStmt, *ast.IfStmt:
+			if i+1 < len(path) {
+				if ifStmt, _ := path[i+1].(*ast.IfStmt); ifStmt != nil && (ifStmt.Body == n || ifStmt.Else == n) {
+					return false
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,352 @@
+package workflow
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// selectorAddFutureFuncs are the qualified names of the methods that add a
+// future to a selector with a callback given the future.
+var selectorAddFutureFuncs = map[string]bool{
+	"(go.temporal.io/sdk/workflow.Selector).AddFuture": true,
+	"(go.temporal.io/sdk/internal.Selector).AddFuture": true,
+}
+
+// FutureConfig is config for NewFutureChecker.
+type FutureConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// FutureChecker checks that futures returned in workflow code are not
+// discarded and that errors from getting their results are checked.
+type FutureChecker struct {
+	*checkerDebug
+}
+
+// NewFutureChecker creates a FutureChecker for the given config.
+func NewFutureChecker(config FutureConfig) *FutureChecker {
+	// Build checker
+	return &FutureChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is a -future-debug flag for enabling debug logs. This analyzer
+// does not have any results but does set *VarFuncs facts on package-level vars.
+func (c *FutureChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowfutures",
+		Doc:       "Analyzes workflow code for discarded futures and unchecked errors from Get",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "future-debug", c.Debug, "show future debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *FutureChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *FutureChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	if !callGraphPackage(pass.Pkg) {
+		return nil
+	}
+	c.debugf("Checking futures of package %v", pass.Pkg.Path())
+	selectedFutures := findSelectedFutures(pass, p.resolver.funcDecls)
+	for _, body := range workflowBodies(p) {
+		c.checkBody(pass, body, selectedFutures)
+	}
+	return nil
+}
+
+// workflowBodies returns the bodies of the workflows found in the package and
+// of every function in the package they reference, directly or through other
+// functions in the package. Bodies nested in another returned body, like
+// function literals, are not returned separately.
+func workflowBodies(p *checkedPackage) []*ast.BlockStmt {
+	var bodies []*ast.BlockStmt
+	seen := map[*types.Func]bool{}
+	var walk func(fn *types.Func, body *ast.BlockStmt)
+	walk = func(fn *types.Func, body *ast.BlockStmt) {
+		if body == nil || (fn != nil && seen[fn]) {
+			return
+		} else if fn != nil {
+			seen[fn] = true
+		}
+		bodies = append(bodies, body)
+		// Functions may be called or given as values (e.g. to workflow.Go)
+		ast.Inspect(body, func(n ast.Node) bool {
+			if ident, _ := n.(*ast.Ident); ident != nil {
+				if fn, _ := p.pass.TypesInfo.Uses[ident].(*types.Func); fn != nil && p.resolver.funcDecls[fn] != nil {
+					walk(fn, p.resolver.funcDecls[fn].Body)
+				}
+			}
+			return true
+		})
+	}
+	for _, root := range p.roots {
+		if root.lit != nil {
+			walk(nil, root.lit.Body)
+		} else if funcDecl := p.resolver.funcDecls[root.fn]; funcDecl != nil {
+			walk(root.fn, funcDecl.Body)
+		}
+	}
+	outermost := make([]*ast.BlockStmt, 0, len(bodies))
+	for _, body := range bodies {
+		nested := false
+		for _, other := range bodies {
+			if other != body && contains(other, body) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			outermost = append(outermost, body)
+		}
+	}
+	return outermost
+}
+
+// findSelectedFutures returns the future vars added to a selector with
+// AddFuture and the future params of the callbacks given to it, whose Get
+// errors may be ignored since the selector already waits on them. Callbacks
+// may be function literals, vars assigned them, or functions in the package.
+func findSelectedFutures(pass *analysis.Pass, funcDecls map[*types.Func]*ast.FuncDecl) map[*types.Var]bool {
+	funcLitVars := map[*types.Var]*ast.FuncLit{}
+	var addFutureCalls []*ast.CallExpr
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if len(n.Lhs) == len(n.Rhs) {
+					for i, lhs := range n.Lhs {
+						if lit, _ := n.Rhs[i].(*ast.FuncLit); lit != nil {
+							if ident, _ := lhs.(*ast.Ident); ident != nil {
+								if v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var); v != nil {
+									funcLitVars[v] = lit
+								}
+							}
+						}
+					}
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) == len(n.Values) {
+					for i, name := range n.Names {
+						if lit, _ := n.Values[i].(*ast.FuncLit); lit != nil {
+							if v, _ := pass.TypesInfo.Defs[name].(*types.Var); v != nil {
+								funcLitVars[v] = lit
+							}
+						}
+					}
+				}
+			case *ast.CallExpr:
+				if callee, _ := typeutil.Callee(pass.TypesInfo, n).(*types.Func); callee != nil &&
+					selectorAddFutureFuncs[callee.FullName()] && len(n.Args) == 2 {
+					addFutureCalls = append(addFutureCalls, n)
+				}
+			}
+			return true
+		})
+	}
+	selected := map[*types.Var]bool{}
+	addFirstParam := func(params *ast.FieldList) {
+		if params != nil && len(params.List) > 0 && len(params.List[0].Names) > 0 {
+			if v, _ := pass.TypesInfo.Defs[params.List[0].Names[0]].(*types.Var); v != nil {
+				selected[v] = true
+			}
+		}
+	}
+	for _, callExpr := range addFutureCalls {
+		if ident := calleeIdent(callExpr.Args[0]); ident != nil {
+			if v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var); v != nil {
+				selected[v] = true
+			}
+		}
+		switch callback := callExpr.Args[1].(type) {
+		case *ast.FuncLit:
+			addFirstParam(callback.Type.Params)
+		case *ast.Ident:
+			switch obj := pass.TypesInfo.ObjectOf(callback).(type) {
+			case *types.Var:
+				if funcLitVars[obj] != nil {
+					addFirstParam(funcLitVars[obj].Type.Params)
+				}
+			case *types.Func:
+				if funcDecls[obj] != nil {
+					addFirstParam(funcDecls[obj].Type.Params)
+				}
+			}
+		}
+	}
+	return selected
+}
+
+// checkBody reports discarded futures and unchecked errors from Get in the
+// given body, including in nested function literals.
+func (c *FutureChecker) checkBody(pass *analysis.Pass, body *ast.BlockStmt, selectedFutures map[*types.Var]bool) {
+	// Calls whose results are discarded and Get errors assigned to vars
+	var discarded []*ast.CallExpr
+	errAssigns := map[*ast.CallExpr]*ast.Ident{}
+	r := &errReads{pass: pass, body: body, writes: map[*ast.Ident]ast.Node{}, uses: map[*types.Var][]*ast.Ident{}}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			if v, _ := pass.TypesInfo.Uses[n].(*types.Var); v != nil {
+				r.uses[v] = append(r.uses[v], n)
+			}
+		case *ast.ExprStmt:
+			if callExpr, _ := n.X.(*ast.CallExpr); callExpr != nil {
+				discarded = append(discarded, callExpr)
+			}
+		case *ast.DeferStmt:
+			discarded = append(discarded, n.Call)
+		case *ast.GoStmt:
+			discarded = append(discarded, n.Call)
+		case *ast.BlockStmt:
+			r.addWrites(n, n.List)
+		case *ast.CaseClause:
+			r.addWrites(n, n.Body)
+		case *ast.CommClause:
+			r.addWrites(n, n.Body)
+		case *ast.AssignStmt:
+			if len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+				if callExpr, _ := n.Rhs[0].(*ast.CallExpr); callExpr != nil {
+					if ident, _ := n.Lhs[0].(*ast.Ident); ident != nil && ident.Name == "_" {
+						discarded = append(discarded, callExpr)
+					} else if ident != nil {
+						errAssigns[callExpr] = ident
+						discarded = append(discarded, callExpr)
+					}
+				}
+			}
+		case *ast.ForStmt, *ast.RangeStmt:
+			r.loops = append(r.loops, n)
+		case *ast.FuncLit:
+			r.lits = append(r.lits, n)
+		}
+		return true
+	})
+	for _, callExpr := range discarded {
+		callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+		if callee == nil {
+			continue
+		}
+		if errIdent := errAssigns[callExpr]; errIdent != nil {
+			// Get errors assigned to a var are only unchecked if never read
+			// after
+			if !futureGetFuncs[callee.FullName()] || r.read(errIdent) {
+				continue
+			}
+		} else if !futureGetFuncs[callee.FullName()] {
+			if isFuture(pass.TypesInfo.TypeOf(callExpr)) {
+				pass.Reportf(callExpr.Pos(), "future returned by %v is discarded", callee.FullName())
+			}
+			continue
+		}
+		sel, _ := callExpr.Fun.(*ast.SelectorExpr)
+		if sel == nil {
+			continue
+		}
+		if ident := calleeIdent(sel.X); ident != nil {
+			if v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var); v != nil && selectedFutures[v] {
+				continue
+			}
+		}
+		pass.Reportf(callExpr.Pos(), "error from Get on future %v is not checked", types.ExprString(sel.X))
+	}
+}
+
+// errReads finds whether vars assigned in a body are read after.
+type errReads struct {
+	pass *analysis.Pass
+	body *ast.BlockStmt
+	// Identifiers only written by an assignment statement with the block or
+	// clause containing the statement
+	writes map[*ast.Ident]ast.Node
+	// Identifiers using each var in the body
+	uses  map[*types.Var][]*ast.Ident
+	loops []ast.Node
+	lits  []*ast.FuncLit
+}
+
+func (r *errReads) addWrites(block ast.Node, stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		if assign, _ := stmt.(*ast.AssignStmt); assign != nil && (assign.Tok == token.ASSIGN || assign.Tok == token.DEFINE) {
+			for _, lhs := range assign.Lhs {
+				if ident, _ := lhs.(*ast.Ident); ident != nil {
+					r.writes[ident] = block
+				}
+			}
+		}
+	}
+}
+
+// read returns true if the var assigned at the given identifier may be read
+// after the assignment. Vars declared outside of the body, like named results,
+// are always considered read, as are reads in a function literal not
+// containing the assignment. Otherwise, a read after the assignment, or
+// anywhere in a loop containing it, is only ignored if the var is first
+// reassigned in the same block.
+func (r *errReads) read(assigned *ast.Ident) bool {
+	v, _ := r.pass.TypesInfo.ObjectOf(assigned).(*types.Var)
+	if v == nil || v.Pos() < r.body.Pos() || v.Pos() >= r.body.End() {
+		return true
+	}
+	from := assigned.Pos()
+	for _, loop := range r.loops {
+		if contains(loop, assigned) && loop.Pos() < from {
+			from = loop.Pos()
+		}
+	}
+	// Reassignment in the same block outside of a loop hides later reads
+	until := token.NoPos
+	if from == assigned.Pos() {
+		for ident, block := range r.writes {
+			if block == r.writes[assigned] && r.pass.TypesInfo.ObjectOf(ident) == v && ident.Pos() > from &&
+				(until == token.NoPos || ident.Pos() < until) {
+				until = ident.Pos()
+			}
+		}
+	}
+	for _, ident := range r.uses[v] {
+		if r.writes[ident] != nil {
+			continue
+		} else if ident.Pos() > from && (until == token.NoPos || ident.Pos() < until) {
+			return true
+		}
+		for _, lit := range r.lits {
+			if contains(lit, ident) && !contains(lit, assigned) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contains(outer, inner ast.Node) bool {
+	return outer.Pos() <= inner.Pos() && inner.End() <= outer.End()
+}
+
+// isFuture returns true if the type is a workflow future or child workflow
+// future, which may be aliases of the internal futures.
+func isFuture(t types.Type) bool {
+	named, _ := t.(*types.Named)
+	if named == nil || named.Obj().Pkg() == nil ||
+		(named.Obj().Name() != "Future" && named.Obj().Name() != "ChildWorkflowFuture") {
+		return false
+	}
+	path := named.Obj().Pkg().Path()
+	return path == "go.temporal.io/sdk/workflow" || path == "go.temporal.io/sdk/internal"
+}
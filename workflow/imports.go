@@ -0,0 +1,230 @@
+package workflow
+
+import (
+	"flag"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// DefaultImportPolicy is the built-in set of packages that packages defining
+// workflows may not import, directly or transitively.
+var DefaultImportPolicy = ImportPolicy{
+	"database/sql": true,
+	"net/http":     true,
+	"os/exec":      true,
+	// Cloud SDKs
+	"cloud.google.com/go/...":               true,
+	"github.com/aws/aws-sdk-go/...":         true,
+	"github.com/aws/aws-sdk-go-v2/...":      true,
+	"github.com/Azure/azure-sdk-for-go/...": true,
+}
+
+// ImportPolicy is a map of whether the key, as a package path, is forbidden
+// from being imported by workflow packages (true value means forbidden, false
+// means allowed). A package path that ends with "/..." applies to that package
+// and all packages beneath it. An exact package path takes precedence over the
+// longest matching "/..." path.
+type ImportPolicy map[string]bool
+
+// Clone copies the map and returns it.
+func (i ImportPolicy) Clone() ImportPolicy {
+	ret := make(ImportPolicy, len(i))
+	for k, v := range i {
+		ret[k] = v
+	}
+	return ret
+}
+
+// SetAllStrings sets values based on the given string values. The strings are
+// package paths and are assumed as "true" (forbidden) unless the string ends
+// with "=false" which is then treated as false in the map.
+func (i ImportPolicy) SetAllStrings(refs []string) ImportPolicy {
+	for _, ref := range refs {
+		if strings.HasSuffix(ref, "=false") {
+			i[strings.TrimSuffix(ref, "=false")] = false
+		} else {
+			i[strings.TrimSuffix(ref, "=true")] = true
+		}
+	}
+	return i
+}
+
+// Forbids returns true if the package path may not be imported.
+func (i ImportPolicy) Forbids(path string) bool {
+	if forbidden, ok := i[path]; ok {
+		return forbidden
+	}
+	// Walk up the path looking for the most specific wildcard
+	for {
+		if forbidden, ok := i[path+"/..."]; ok {
+			return forbidden
+		}
+		lastSlash := strings.LastIndex(path, "/")
+		if lastSlash == -1 {
+			return false
+		}
+		path = path[:lastSlash]
+	}
+}
+
+type importPolicyFlag struct{ policy ImportPolicy }
+
+// NewImportPolicyFlag creates a flag.Value implementation for using
+// ImportPolicy.SetAllStrings as a CLI flag value.
+func NewImportPolicyFlag(policy ImportPolicy) flag.Value { return importPolicyFlag{policy} }
+
+func (importPolicyFlag) String() string { return "<built-in>" }
+
+func (i importPolicyFlag) Set(flag string) error {
+	i.policy.SetAllStrings(strings.Split(flag, ","))
+	return nil
+}
+
+// ImportChains is the package fact of forbidden packages a package imports,
+// directly or transitively. The key is the forbidden package path and the
+// value is the shortest import chain from the package to it, not including the
+// package itself.
+type ImportChains map[string][]string
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*ImportChains) AFact() {}
+
+// String returns all chains as a comma-delimited string sorted by forbidden
+// package path.
+func (i *ImportChains) String() string {
+	if i == nil {
+		return "<none>"
+	}
+	paths := make([]string, 0, len(*i))
+	for path := range *i {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	chains := make([]string, len(paths))
+	for index, path := range paths {
+		chains[index] = strings.Join((*i)[path], " -> ")
+	}
+	return strings.Join(chains, ", ")
+}
+
+// ImportConfig is config for NewImportChecker.
+type ImportConfig struct {
+	// If empty, uses DefaultImportPolicy.
+	DefaultPolicy ImportPolicy
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// ImportChecker checks that packages defining functions passed to
+// RegisterWorkflow do not import packages forbidden by a policy.
+type ImportChecker struct {
+	*checkerDebug
+	Policy ImportPolicy
+}
+
+// NewImportChecker creates an ImportChecker for the given config.
+func NewImportChecker(config ImportConfig) *ImportChecker {
+	// Set default policy and clone
+	if config.DefaultPolicy == nil {
+		config.DefaultPolicy = DefaultImportPolicy
+	}
+	config.DefaultPolicy = config.DefaultPolicy.Clone()
+	// Build checker
+	return &ImportChecker{
+		Policy:       config.DefaultPolicy,
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is a -forbid-import flag for adding policy overrides and an
+// -import-debug flag for enabling debug logs. This analyzer does not have any
+// results but does set *ImportChains facts on packages and *VarFuncs facts on
+// package-level vars.
+func (c *ImportChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowimports",
+		Doc:       "Analyzes packages of all RegisterWorkflow functions for forbidden imports",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&ImportChains{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.Var(NewImportPolicyFlag(c.Policy), "forbid-import",
+		"package path (optionally ending in '/...') workflow packages may not import, overriding the default "+
+			"(append '=false' to allow)")
+	a.Flags.BoolVar(&c.Debug, "import-debug", c.Debug, "show import debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *ImportChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *ImportChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	c.debugf("Checking imports of package %v", pass.Pkg.Path())
+	// Collect forbidden imports of this package and set as fact if non-empty
+	chains := c.importChains(pass)
+	if len(chains) > 0 {
+		pass.ExportPackageFact(&chains)
+	}
+	// Check the package of each registered workflow once per pass
+	checked := map[*types.Package]bool{}
+	for _, root := range p.roots {
+		pkg := root.pkg(pass)
+		if pkg == nil || checked[pkg] {
+			continue
+		}
+		checked[pkg] = true
+		pkgChains := chains
+		if pkg != pass.Pkg {
+			pkgChains = nil
+			pass.ImportPackageFact(pkg, &pkgChains)
+		}
+		// One report per forbidden import, sorted for determinism
+		paths := make([]string, 0, len(pkgChains))
+		for path := range pkgChains {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			chain := pkgChains[path]
+			if len(chain) == 1 {
+				pass.Reportf(root.pos, "workflow package %v imports forbidden package %v", pkg.Path(), path)
+			} else {
+				pass.Reportf(root.pos, "workflow package %v imports forbidden package %v via %v -> %v",
+					pkg.Path(), path, pkg.Path(), strings.Join(chain, " -> "))
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ImportChecker) importChains(pass *analysis.Pass) ImportChains {
+	chains := ImportChains{}
+	for _, imp := range pass.Pkg.Imports() {
+		// Direct imports are always the shortest chain
+		if c.Policy.Forbids(imp.Path()) {
+			chains[imp.Path()] = []string{imp.Path()}
+		}
+		// Prepend this import to the chains of the imported package if shorter.
+		// Chains through the Temporal SDK are not followed since workflows must
+		// import it and it reaches net/http internally.
+		var impChains ImportChains
+		if isSDKPackage(imp.Path()) || !pass.ImportPackageFact(imp, &impChains) {
+			continue
+		}
+		for path, chain := range impChains {
+			if existing, ok := chains[path]; !ok || len(chain)+1 < len(existing) {
+				chains[path] = append([]string{imp.Path()}, chain...)
+			}
+		}
+	}
+	return chains
+}
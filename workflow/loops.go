@@ -0,0 +1,383 @@
+package workflow
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/cretz/temporal-determinist/determinism"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// UnboundedLoops is the object fact of loops in a function without a static
+// bound that issue workflow commands but never continue as new or check the
+// history length.
+type UnboundedLoops []*UnboundedLoop
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*UnboundedLoops) AFact() {}
+
+// String returns all loops as a comma-delimited string.
+func (u *UnboundedLoops) String() string {
+	if u == nil {
+		return "<none>"
+	}
+	strs := make([]string, len(*u))
+	for i, loop := range *u {
+		strs[i] = loop.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// UnboundedLoop is a loop without a static bound issuing workflow commands.
+type UnboundedLoop struct {
+	Pos token.Position
+	// The first workflow command function called in the loop
+	Command *types.Func
+	// The function in the same package called in the loop that calls the
+	// command if not called directly
+	Via *types.Func
+}
+
+// String returns the loop without the position.
+func (u *UnboundedLoop) String() string {
+	str := "loop without a static bound calls " + u.Command.FullName()
+	if u.Via != nil {
+		str += " via " + u.Via.FullName()
+	}
+	return str + " without continuing as new or checking history length"
+}
+
+// workflowCommandFuncs are the qualified names of functions that add to the
+// workflow history.
+var workflowCommandFuncs = map[string]bool{
+	"go.temporal.io/sdk/workflow.ExecuteActivity":                     true,
+	"go.temporal.io/sdk/internal.ExecuteActivity":                     true,
+	"go.temporal.io/sdk/workflow.ExecuteLocalActivity":                true,
+	"go.temporal.io/sdk/internal.ExecuteLocalActivity":                true,
+	"go.temporal.io/sdk/workflow.ExecuteChildWorkflow":                true,
+	"go.temporal.io/sdk/internal.ExecuteChildWorkflow":                true,
+	"go.temporal.io/sdk/workflow.NewTimer":                            true,
+	"go.temporal.io/sdk/internal.NewTimer":                            true,
+	"go.temporal.io/sdk/workflow.Sleep":                               true,
+	"go.temporal.io/sdk/internal.Sleep":                               true,
+	"go.temporal.io/sdk/workflow.SignalExternalWorkflow":              true,
+	"go.temporal.io/sdk/internal.SignalExternalWorkflow":              true,
+	"go.temporal.io/sdk/workflow.RequestCancelExternalWorkflow":       true,
+	"go.temporal.io/sdk/internal.RequestCancelExternalWorkflow":       true,
+	"go.temporal.io/sdk/workflow.SideEffect":                          true,
+	"go.temporal.io/sdk/internal.SideEffect":                          true,
+	"go.temporal.io/sdk/workflow.MutableSideEffect":                   true,
+	"go.temporal.io/sdk/internal.MutableSideEffect":                   true,
+	"(go.temporal.io/sdk/workflow.ReceiveChannel).Receive":            true,
+	"(go.temporal.io/sdk/internal.ReceiveChannel).Receive":            true,
+	"(go.temporal.io/sdk/workflow.ReceiveChannel).ReceiveWithTimeout": true,
+	"(go.temporal.io/sdk/internal.ReceiveChannel).ReceiveWithTimeout": true,
+	"(go.temporal.io/sdk/workflow.Selector).Select":                   true,
+	"(go.temporal.io/sdk/internal.Selector).Select":                   true,
+}
+
+// workflowGoFuncs are the qualified names of functions that run a function as
+// a workflow coroutine.
+var workflowGoFuncs = map[string]bool{
+	"go.temporal.io/sdk/workflow.Go":      true,
+	"go.temporal.io/sdk/internal.Go":      true,
+	"go.temporal.io/sdk/workflow.GoNamed": true,
+	"go.temporal.io/sdk/internal.GoNamed": true,
+}
+
+// historyCheckFuncs are the qualified names of functions that check the
+// length of the workflow history.
+var historyCheckFuncs = map[string]bool{
+	"(*go.temporal.io/sdk/workflow.Info).GetCurrentHistoryLength":           true,
+	"(*go.temporal.io/sdk/internal.WorkflowInfo).GetCurrentHistoryLength":   true,
+	"(*go.temporal.io/sdk/workflow.Info).GetContinueAsNewSuggested":         true,
+	"(*go.temporal.io/sdk/internal.WorkflowInfo).GetContinueAsNewSuggested": true,
+}
+
+// LoopConfig is config for NewLoopChecker.
+type LoopConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// LoopChecker checks that workflows looping without a static bound while
+// issuing workflow commands eventually continue as new, since the history
+// otherwise grows past its limits.
+type LoopChecker struct {
+	*checkerDebug
+}
+
+// NewLoopChecker creates a LoopChecker for the given config.
+func NewLoopChecker(config LoopConfig) *LoopChecker {
+	// Build checker
+	return &LoopChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is a -loop-debug flag for enabling debug logs. This analyzer
+// does not have any results but does set *UnboundedLoops facts on functions and
+// *VarFuncs facts on package-level vars.
+func (c *LoopChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowloops",
+		Doc:       "Analyzes workflows for unbounded loops that never continue as new",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&UnboundedLoops{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "loop-debug", c.Debug, "show loop debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *LoopChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *LoopChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	if !callGraphPackage(pass.Pkg) {
+		return nil
+	}
+	c.debugf("Checking loops of package %v", pass.Pkg.Path())
+	f := &loopFinder{
+		pass:      pass,
+		funcDecls: p.resolver.funcDecls,
+		commands:  map[*types.Func]*types.Func{},
+		continues: map[*types.Func]bool{},
+		walking:   map[*types.Func]bool{},
+	}
+	// Set facts for every function with unbounded loops
+	for fn, funcDecl := range f.funcDecls {
+		if funcDecl.Body == nil {
+			continue
+		}
+		if loops := f.unboundedLoops(funcDecl.Body); len(loops) > 0 {
+			c.debugf("Marking %v as having unbounded loops", fn.FullName())
+			pass.ExportObjectFact(fn, &loops)
+		}
+	}
+	// Report each for every workflow
+	for _, root := range p.roots {
+		if root.interceptor {
+			continue
+		}
+		var loops UnboundedLoops
+		p.rootFact(root, &loops, func(lit *ast.FuncLit) { loops = f.unboundedLoops(lit.Body) })
+		for _, loop := range loops {
+			pass.Reportf(root.pos, "%v may exceed history limits, reason: %v at %v",
+				root.subject(), loop, determinism.RelativePosition(loop.Pos))
+		}
+	}
+	return nil
+}
+
+// loopFinder finds unbounded loops, following calls to functions in the same
+// package for workflow commands and continuing as new.
+type loopFinder struct {
+	pass      *analysis.Pass
+	funcDecls map[*types.Func]*ast.FuncDecl
+	// First workflow command called by functions in this package already
+	// walked, nil if none
+	commands map[*types.Func]*types.Func
+	// Whether functions in this package already walked continue as new or
+	// check history length
+	continues map[*types.Func]bool
+	// Functions currently being walked to prevent recursion
+	walking map[*types.Func]bool
+}
+
+// unboundedLoops returns the outermost loops without a static bound in the
+// given body, including in function literals given to workflow.Go but not
+// other function literals, that call workflow commands but never continue as
+// new or check history length in the loop.
+func (f *loopFinder) unboundedLoops(body *ast.BlockStmt) (loops UnboundedLoops) {
+	// Function literals run as coroutines of the body
+	coroutines := map[*ast.FuncLit]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		var loopBody *ast.BlockStmt
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if callee, _ := typeutil.Callee(f.pass.TypesInfo, n).(*types.Func); callee != nil &&
+				workflowGoFuncs[callee.FullName()] {
+				for _, arg := range n.Args {
+					if lit, _ := arg.(*ast.FuncLit); lit != nil {
+						coroutines[lit] = true
+					}
+				}
+			}
+			return true
+		case *ast.FuncLit:
+			return coroutines[n]
+		case *ast.ForStmt:
+			loopBody = n.Body
+		case *ast.RangeStmt:
+			loopBody = n.Body
+		default:
+			return true
+		}
+		if loopBounded(f.pass, n.(ast.Stmt)) || f.nodeContinues(n) {
+			return true
+		}
+		if command, via := f.nodeCommand(loopBody); command != nil {
+			loops = append(loops, &UnboundedLoop{Pos: f.pass.Fset.Position(n.Pos()), Command: command, Via: via})
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// nodeCommand returns the first workflow command called in the given node, not
+// including function literals, and the function in this package it is called
+// through if not called directly.
+func (f *loopFinder) nodeCommand(node ast.Node) (command, via *types.Func) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if command != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			callee, _ := typeutil.Callee(f.pass.TypesInfo, n).(*types.Func)
+			if callee == nil {
+				return true
+			} else if workflowCommandFuncs[callee.FullName()] {
+				command = callee
+			} else if calleeCommand := f.funcCommand(callee); calleeCommand != nil {
+				command, via = calleeCommand, callee
+			}
+		}
+		return true
+	})
+	return
+}
+
+// funcCommand returns the first workflow command called by the given function
+// in this package, directly or transitively.
+func (f *loopFinder) funcCommand(fn *types.Func) *types.Func {
+	if command, ok := f.commands[fn]; ok {
+		return command
+	} else if f.walking[fn] || f.funcDecls[fn] == nil || f.funcDecls[fn].Body == nil {
+		return nil
+	}
+	f.walking[fn] = true
+	defer delete(f.walking, fn)
+	command, _ := f.nodeCommand(f.funcDecls[fn].Body)
+	f.commands[fn] = command
+	return command
+}
+
+// nodeContinues returns true if the given node, not including function
+// literals, creates a continue-as-new error or checks history length, directly
+// or through functions in this package.
+func (f *loopFinder) nodeContinues(node ast.Node) (continues bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if continues {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if callee, _ := typeutil.Callee(f.pass.TypesInfo, n).(*types.Func); callee != nil {
+				continues = continueAsNewErrorFuncs[callee.FullName()] || historyCheckFuncs[callee.FullName()] ||
+					f.funcContinues(callee)
+			}
+		}
+		return true
+	})
+	return
+}
+
+// funcContinues returns true if the given function in this package creates a
+// continue-as-new error or checks history length, directly or transitively.
+func (f *loopFinder) funcContinues(fn *types.Func) bool {
+	if continues, ok := f.continues[fn]; ok {
+		return continues
+	} else if f.walking[fn] || f.funcDecls[fn] == nil || f.funcDecls[fn].Body == nil {
+		return false
+	}
+	f.walking[fn] = true
+	defer delete(f.walking, fn)
+	continues := f.nodeContinues(f.funcDecls[fn].Body)
+	f.continues[fn] = continues
+	return continues
+}
+
+// loopBounded returns true if the loop is a range over anything but a channel
+// or has a condition comparing against a constant, a length, or a var not
+// assigned in the loop.
+func loopBounded(pass *analysis.Pass, loop ast.Stmt) bool {
+	switch loop := loop.(type) {
+	case *ast.RangeStmt:
+		_, isChan := pass.TypesInfo.TypeOf(loop.X).Underlying().(*types.Chan)
+		return !isChan
+	case *ast.ForStmt:
+		cond, _ := loop.Cond.(*ast.BinaryExpr)
+		if cond == nil {
+			return false
+		}
+		switch cond.Op {
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return staticBound(pass, loop, cond.X) || staticBound(pass, loop, cond.Y)
+		}
+	}
+	return false
+}
+
+// staticBound returns true if the expression is a constant, a call to len or
+// cap, or a var not assigned in the given loop.
+func staticBound(pass *analysis.Pass, loop *ast.ForStmt, expr ast.Expr) bool {
+	if pass.TypesInfo.Types[expr].Value != nil {
+		return true
+	}
+	switch expr := expr.(type) {
+	case *ast.CallExpr:
+		if ident := calleeIdent(expr.Fun); ident != nil {
+			if _, isBuiltin := pass.TypesInfo.ObjectOf(ident).(*types.Builtin); isBuiltin {
+				return ident.Name == "len" || ident.Name == "cap"
+			}
+		}
+	case *ast.Ident:
+		v, _ := pass.TypesInfo.ObjectOf(expr).(*types.Var)
+		return v != nil && !varAssigned(pass, v, loop.Cond, loop.Post, loop.Body)
+	}
+	return false
+}
+
+// varAssigned returns true if the var is assigned, incremented, decremented, or
+// has its address taken in any of the given nodes.
+func varAssigned(pass *analysis.Pass, v *types.Var, nodes ...ast.Node) (assigned bool) {
+	isVar := func(expr ast.Expr) bool {
+		ident := calleeIdent(expr)
+		return ident != nil && pass.TypesInfo.ObjectOf(ident) == v
+	}
+	for _, node := range nodes {
+		if node == nil || assigned {
+			continue
+		}
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					assigned = assigned || isVar(lhs)
+				}
+			case *ast.IncDecStmt:
+				assigned = assigned || isVar(n.X)
+			case *ast.UnaryExpr:
+				assigned = assigned || (n.Op == token.AND && isVar(n.X))
+			}
+			return !assigned
+		})
+	}
+	return
+}
@@ -0,0 +1,328 @@
+package workflow
+
+import (
+	"flag"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/cretz/temporal-determinist/determinism"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Names is the package fact of workflow and activity type names registered in
+// a package or any of its dependencies. Since facts only flow from a package to
+// the packages importing it, registrations in packages outside of this import
+// closure are never known.
+type Names struct {
+	// Registered type names keyed by kind, either "workflow" or "activity"
+	Registered map[string]map[string]bool
+	// Kinds that have dynamic registrations accepting any name
+	Dynamic map[string]bool
+}
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*Names) AFact() {}
+
+// String returns the sorted registered names.
+func (n *Names) String() string {
+	if n == nil {
+		return "<none>"
+	}
+	var registered []string
+	for kind, names := range n.Registered {
+		for name := range names {
+			registered = append(registered, kind+" "+name)
+		}
+	}
+	for kind := range n.Dynamic {
+		registered = append(registered, "dynamic "+kind)
+	}
+	sort.Strings(registered)
+	return "registered " + strings.Join(registered, ", ")
+}
+
+// NamedInvocations is the object fact of executions by type name a function
+// makes, directly or through other functions it calls.
+type NamedInvocations []*NamedInvocation
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*NamedInvocations) AFact() {}
+
+// String returns all invocations as a comma-delimited string.
+func (n *NamedInvocations) String() string {
+	if n == nil {
+		return "<none>"
+	}
+	strs := make([]string, len(*n))
+	for i, invocation := range *n {
+		strs[i] = invocation.Kind + " " + invocation.Name
+	}
+	return strings.Join(strs, ", ")
+}
+
+// NamedInvocation is an execution of an activity or child workflow by type
+// name.
+type NamedInvocation struct {
+	// Either "workflow" or "activity"
+	Kind string
+	Name string
+	Pos  token.Position
+	// The function the execution is made in
+	Func *types.Func
+}
+
+// NameConfig is config for NewNameChecker.
+type NameConfig struct {
+	// Type names registered outside of the analyzed packages that may be
+	// invoked by name.
+	ExternalNames []string
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// NameChecker checks that activities and child workflows executed by type
+// name are registered.
+type NameChecker struct {
+	*checkerDebug
+	ExternalNames []string
+}
+
+// NewNameChecker creates a NameChecker for the given config.
+func NewNameChecker(config NameConfig) *NameChecker {
+	// Build checker
+	return &NameChecker{
+		ExternalNames: append([]string(nil), config.ExternalNames...),
+		checkerDebug:  newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is an -external-name flag for adding externally registered names
+// and a -name-debug flag for enabling debug logs. This analyzer does not have
+// any results but does set *Names facts on packages, *NamedInvocations facts on
+// functions, and *VarFuncs facts on package-level vars.
+func (c *NameChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflownames",
+		Doc:       "Analyzes activities and child workflows executed by name for missing registrations",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&Names{}, &NamedInvocations{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.Var(NewExternalNamesFlag(&c.ExternalNames), "external-name",
+		"workflow or activity type name registered outside of the analyzed packages")
+	a.Flags.BoolVar(&c.Debug, "name-debug", c.Debug, "show name debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *NameChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *NameChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	c.debugf("Checking names of package %v", pass.Pkg.Path())
+	names := &Names{Registered: map[string]map[string]bool{}, Dynamic: map[string]bool{}}
+	// Merge dependencies
+	for _, imp := range pass.Pkg.Imports() {
+		var impNames Names
+		if !pass.ImportPackageFact(imp, &impNames) {
+			continue
+		}
+		for kind, kindNames := range impNames.Registered {
+			for name := range kindNames {
+				names.register(kind, name)
+			}
+		}
+		for kind := range impNames.Dynamic {
+			names.Dynamic[kind] = true
+		}
+	}
+	// Set facts for every function executing by name
+	f := &namedInvocationFinder{
+		NameChecker: c,
+		pass:        pass,
+		resolver:    p.resolver,
+		results:     map[*types.Func]NamedInvocations{},
+		walking:     map[*types.Func]bool{},
+		positions:   map[token.Position]token.Pos{},
+	}
+	for fn := range f.resolver.funcDecls {
+		f.funcInvocations(fn)
+	}
+	// Collect registrations in this package and the workflows registered
+	w := &registrationWalker{pass: pass, resolver: f.resolver}
+	var workflows []*root
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			callExpr, _ := n.(*ast.CallExpr)
+			if callExpr == nil || len(callExpr.Args) == 0 {
+				return true
+			}
+			callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+			if callee == nil {
+				return true
+			}
+			if call, ok := registrationCalls[callee.FullName()]; ok {
+				for _, reg := range w.callRegistrations(callExpr, call.kind, call.optsIndex) {
+					names.register(reg.Kind, reg.Name)
+				}
+				if call.kind == "workflow" {
+					workflows = append(workflows, f.resolver.resolve(callExpr.Pos(), callExpr.Args[0])...)
+				}
+			} else if callee.FullName() == dynamicRegistrationName {
+				names.Dynamic["workflow"] = true
+				workflows = append(workflows, f.resolver.resolve(callExpr.Pos(), callExpr.Args[0])...)
+			}
+			return true
+		})
+	}
+	// Only executions reachable from the workflows registered in this package
+	// are checked. Ones in this package are reported where they are made and
+	// others at the registration.
+	seen := map[token.Position]bool{}
+	for _, workflow := range workflows {
+		var invocations NamedInvocations
+		p.rootFact(workflow, &invocations, func(lit *ast.FuncLit) { invocations = f.nodeInvocations(nil, lit.Body) })
+		for _, invocation := range invocations {
+			if seen[invocation.Pos] || names.known(invocation, c.ExternalNames) {
+				continue
+			}
+			seen[invocation.Pos] = true
+			if pos, ok := f.positions[invocation.Pos]; ok {
+				pass.Reportf(pos, "%v %q is not registered", invocation.Kind, invocation.Name)
+			} else {
+				pass.Reportf(workflow.pos, "%v %q executed at %v is not registered",
+					invocation.Kind, invocation.Name, determinism.RelativePosition(invocation.Pos))
+			}
+		}
+	}
+	if len(names.Registered) > 0 || len(names.Dynamic) > 0 {
+		pass.ExportPackageFact(names)
+	}
+	return nil
+}
+
+type namedInvocationFinder struct {
+	*NameChecker
+	pass     *analysis.Pass
+	resolver *rootResolver
+	// Invocations of functions in this package already found
+	results map[*types.Func]NamedInvocations
+	// Functions currently being walked to prevent recursion
+	walking map[*types.Func]bool
+	// Positions of invocations made in this package
+	positions map[token.Position]token.Pos
+}
+
+// funcInvocations returns the invocations by name of the given function in
+// this package, setting the fact if there are any.
+func (f *namedInvocationFinder) funcInvocations(fn *types.Func) NamedInvocations {
+	if invocations, ok := f.results[fn]; ok {
+		return invocations
+	}
+	funcDecl := f.resolver.funcDecls[fn]
+	if funcDecl == nil || funcDecl.Body == nil || f.walking[fn] {
+		return nil
+	}
+	f.walking[fn] = true
+	defer delete(f.walking, fn)
+	invocations := f.nodeInvocations(fn, funcDecl.Body)
+	f.results[fn] = invocations
+	if len(invocations) > 0 {
+		f.debugf("Marking %v as executing by name", fn.FullName())
+		f.pass.ExportObjectFact(fn, &invocations)
+	}
+	return invocations
+}
+
+// nodeInvocations returns the invocations by name in the given node of the
+// given function, including those of the functions it calls.
+func (f *namedInvocationFinder) nodeInvocations(fn *types.Func, node ast.Node) (invocations NamedInvocations) {
+	seen := map[token.Position]bool{}
+	add := func(invocation *NamedInvocation) {
+		if !seen[invocation.Pos] {
+			seen[invocation.Pos] = true
+			invocations = append(invocations, invocation)
+		}
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		callExpr, _ := n.(*ast.CallExpr)
+		if callExpr == nil {
+			return true
+		}
+		callee := typeutil.StaticCallee(f.pass.TypesInfo, callExpr)
+		if callee == nil || callee.Pkg() == nil {
+			return true
+		}
+		// Invocations by a constant name
+		if exec, ok := executeCalls[callee.FullName()]; ok {
+			if exec.fnIndex < len(callExpr.Args) {
+				if val := f.pass.TypesInfo.Types[callExpr.Args[exec.fnIndex]].Value; val != nil && val.Kind() == constant.String {
+					kind := exec.kind
+					if kind == "child workflow" {
+						kind = "workflow"
+					}
+					pos := f.pass.Fset.Position(callExpr.Pos())
+					f.positions[pos] = callExpr.Pos()
+					add(&NamedInvocation{Kind: kind, Name: constant.StringVal(val), Pos: pos, Func: fn})
+				}
+			}
+			return true
+		} else if !callGraphPackage(callee.Pkg()) {
+			return true
+		}
+		var calleeInvocations NamedInvocations
+		if callee.Pkg() == f.pass.Pkg {
+			calleeInvocations = f.funcInvocations(callee)
+		} else {
+			f.pass.ImportObjectFact(callee, &calleeInvocations)
+		}
+		for _, invocation := range calleeInvocations {
+			add(invocation)
+		}
+		return true
+	})
+	return
+}
+
+func (n *Names) register(kind, name string) {
+	if n.Registered[kind] == nil {
+		n.Registered[kind] = map[string]bool{}
+	}
+	n.Registered[kind][name] = true
+}
+
+// known returns true if the invocation is registered or external.
+func (n *Names) known(invocation *NamedInvocation, externalNames []string) bool {
+	if n.Registered[invocation.Kind][invocation.Name] || n.Dynamic[invocation.Kind] {
+		return true
+	}
+	for _, name := range externalNames {
+		if name == invocation.Name {
+			return true
+		}
+	}
+	return false
+}
+
+type externalNamesFlag struct{ names *[]string }
+
+// NewExternalNamesFlag creates a flag.Value implementation for appending
+// comma-delimited type names as a CLI flag value.
+func NewExternalNamesFlag(names *[]string) flag.Value { return externalNamesFlag{names} }
+
+func (externalNamesFlag) String() string { return "<none>" }
+
+func (e externalNamesFlag) Set(flag string) error {
+	*e.names = append(*e.names, strings.Split(flag, ",")...)
+	return nil
+}
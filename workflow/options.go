@@ -0,0 +1,374 @@
+package workflow
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// optionKind is a kind of options set on a context for executing activities or
+// child workflows.
+type optionKind struct {
+	name string
+	// Fields of which at least one must be set
+	required []string
+	// Fields that may not be negative
+	timeouts []string
+}
+
+var (
+	activityOptionKind = &optionKind{
+		name:     "activity",
+		required: []string{"StartToCloseTimeout", "ScheduleToCloseTimeout"},
+		timeouts: []string{"ScheduleToCloseTimeout", "ScheduleToStartTimeout", "StartToCloseTimeout", "HeartbeatTimeout"},
+	}
+	localActivityOptionKind = &optionKind{
+		name:     "local activity",
+		required: []string{"StartToCloseTimeout", "ScheduleToCloseTimeout"},
+		timeouts: []string{"ScheduleToCloseTimeout", "StartToCloseTimeout"},
+	}
+	childWorkflowOptionKind = &optionKind{
+		name:     "child workflow",
+		timeouts: []string{"WorkflowExecutionTimeout", "WorkflowRunTimeout", "WorkflowTaskTimeout"},
+	}
+)
+
+// optionExecuteCalls are the qualified names of functions that execute using
+// options from the context given as the first argument, with the kind of
+// options used. The function executed is the second argument.
+var optionExecuteCalls = map[string]*optionKind{
+	"go.temporal.io/sdk/workflow.ExecuteActivity":      activityOptionKind,
+	"go.temporal.io/sdk/internal.ExecuteActivity":      activityOptionKind,
+	"go.temporal.io/sdk/workflow.ExecuteLocalActivity": localActivityOptionKind,
+	"go.temporal.io/sdk/internal.ExecuteLocalActivity": localActivityOptionKind,
+	"go.temporal.io/sdk/workflow.ExecuteChildWorkflow": childWorkflowOptionKind,
+	"go.temporal.io/sdk/internal.ExecuteChildWorkflow": childWorkflowOptionKind,
+}
+
+// contextOptionFuncs are the qualified names of functions that replace the
+// options of a kind on the context given as the first argument with the
+// options given as the second argument.
+var contextOptionFuncs = map[string]*optionKind{
+	"go.temporal.io/sdk/workflow.WithActivityOptions":      activityOptionKind,
+	"go.temporal.io/sdk/internal.WithActivityOptions":      activityOptionKind,
+	"go.temporal.io/sdk/workflow.WithLocalActivityOptions": localActivityOptionKind,
+	"go.temporal.io/sdk/internal.WithLocalActivityOptions": localActivityOptionKind,
+	"go.temporal.io/sdk/workflow.WithChildOptions":         childWorkflowOptionKind,
+	"go.temporal.io/sdk/internal.WithChildOptions":         childWorkflowOptionKind,
+}
+
+// contextTimeoutFuncs are the qualified names of functions that set a
+// required field of the options of a kind on the context.
+var contextTimeoutFuncs = map[string]*optionKind{
+	"go.temporal.io/sdk/workflow.WithStartToCloseTimeout":    activityOptionKind,
+	"go.temporal.io/sdk/internal.WithStartToCloseTimeout":    activityOptionKind,
+	"go.temporal.io/sdk/workflow.WithScheduleToCloseTimeout": activityOptionKind,
+	"go.temporal.io/sdk/internal.WithScheduleToCloseTimeout": activityOptionKind,
+}
+
+// OptionConfig is config for NewOptionChecker.
+type OptionConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// OptionChecker checks that the options on the context used to execute
+// activities and child workflows are valid, following the context back to
+// where the options are set.
+type OptionChecker struct {
+	*checkerDebug
+}
+
+// NewOptionChecker creates an OptionChecker for the given config.
+func NewOptionChecker(config OptionConfig) *OptionChecker {
+	// Build checker
+	return &OptionChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is an -option-debug flag for enabling debug logs. This analyzer
+// does not have any results but does set *VarFuncs facts on package-level vars.
+func (c *OptionChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowoptions",
+		Doc:       "Analyzes activity and child workflow executions for missing or invalid context options",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "option-debug", c.Debug, "show option debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *OptionChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *OptionChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	if !callGraphPackage(pass.Pkg) {
+		return nil
+	}
+	c.debugf("Checking options of package %v", pass.Pkg.Path())
+	f := newOptionFinder(p)
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			callExpr, _ := n.(*ast.CallExpr)
+			if callExpr == nil || len(callExpr.Args) < 2 {
+				return true
+			}
+			callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+			if callee == nil {
+				return true
+			}
+			if kind := optionExecuteCalls[callee.FullName()]; kind != nil {
+				if problem := f.contextProblem(callExpr.Args[0], kind); problem != "" {
+					pass.Reportf(callExpr.Pos(), "%v %v executed %v", kind.name, types.ExprString(callExpr.Args[1]), problem)
+				}
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+type optionFinder struct {
+	pass     *analysis.Pass
+	resolver *rootResolver
+	// Context params of workflows in this package, which have no options
+	rootContexts map[*types.Var]bool
+	// Params of all functions, whose values are unknown
+	params map[*types.Var]bool
+	// Fields assigned on each var
+	fieldWrites map[*types.Var]map[string]bool
+	// Options vars currently being followed to prevent recursion
+	vars map[*types.Var]bool
+}
+
+func newOptionFinder(p *checkedPackage) *optionFinder {
+	pass := p.pass
+	f := &optionFinder{
+		pass:         pass,
+		resolver:     p.resolver,
+		rootContexts: map[*types.Var]bool{},
+		params:       map[*types.Var]bool{},
+		fieldWrites:  map[*types.Var]map[string]bool{},
+		vars:         map[*types.Var]bool{},
+	}
+	// Interceptors are given contexts from the workflow
+	for _, root := range p.roots {
+		var funcType *ast.FuncType
+		if root.interceptor {
+			continue
+		} else if root.lit != nil {
+			funcType = root.lit.Type
+		} else if funcDecl := f.resolver.funcDecls[root.fn]; funcDecl != nil {
+			funcType = funcDecl.Type
+		}
+		if funcType == nil || len(funcType.Params.List) == 0 || len(funcType.Params.List[0].Names) == 0 {
+			continue
+		}
+		if v, _ := pass.TypesInfo.Defs[funcType.Params.List[0].Names[0]].(*types.Var); v != nil && isWorkflowContext(v.Type()) {
+			f.rootContexts[v] = true
+		}
+	}
+	addParams := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				if v, _ := pass.TypesInfo.Defs[name].(*types.Var); v != nil {
+					f.params[v] = true
+				}
+			}
+		}
+	}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				addParams(n.Recv)
+			case *ast.FuncType:
+				addParams(n.Params)
+				addParams(n.Results)
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					sel, _ := lhs.(*ast.SelectorExpr)
+					if sel == nil {
+						continue
+					}
+					if ident := calleeIdent(sel.X); ident != nil {
+						if v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var); v != nil {
+							if f.fieldWrites[v] == nil {
+								f.fieldWrites[v] = map[string]bool{}
+							}
+							f.fieldWrites[v][sel.Sel.Name] = true
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	return f
+}
+
+// contextProblem returns a problem with the options of the given kind on the
+// given context expression or empty if no problem can be found. Contexts that
+// cannot be followed are assumed to have valid options.
+func (f *optionFinder) contextProblem(expr ast.Expr, kind *optionKind) string {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return f.contextProblem(expr.X, kind)
+	case *ast.Ident:
+		// Only values assigned before are followed, so there is no recursion
+		v, _ := f.pass.TypesInfo.ObjectOf(expr).(*types.Var)
+		if v == nil {
+			return ""
+		}
+		values, initial := f.reachingValues(v, expr.Pos())
+		for _, value := range values {
+			if problem := f.contextProblem(value, kind); problem != "" {
+				return problem
+			}
+		}
+		if initial && f.rootContexts[v] && len(kind.required) > 0 {
+			return "without " + kind.name + " options on its context"
+		}
+	case *ast.CallExpr:
+		callee, _ := typeutil.Callee(f.pass.TypesInfo, expr).(*types.Func)
+		if callee == nil || len(expr.Args) == 0 {
+			return ""
+		}
+		if optionKind := contextOptionFuncs[callee.FullName()]; optionKind == kind && len(expr.Args) == 2 {
+			return f.optionsProblem(expr.Args[1], kind, false)
+		} else if contextTimeoutFuncs[callee.FullName()] == kind {
+			return ""
+		}
+		// Other SDK functions deriving a context keep the options of the parent
+		if callee.Pkg() == nil || (callee.Pkg().Path() != "go.temporal.io/sdk/workflow" &&
+			callee.Pkg().Path() != "go.temporal.io/sdk/internal") {
+			return ""
+		}
+		sig, _ := callee.Type().(*types.Signature)
+		if sig != nil && sig.Params().Len() > 0 && isWorkflowContext(sig.Params().At(0).Type()) &&
+			sig.Results().Len() == 1 && isWorkflowContext(sig.Results().At(0).Type()) {
+			return f.contextProblem(expr.Args[0], kind)
+		}
+	}
+	return ""
+}
+
+// optionsProblem returns a problem with the given options expression of the
+// given kind or empty if no problem can be found. If satisfied is true, a
+// required field is already known to be set.
+func (f *optionFinder) optionsProblem(expr ast.Expr, kind *optionKind, satisfied bool) string {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return f.optionsProblem(expr.X, kind, satisfied)
+	case *ast.CompositeLit:
+		for _, elt := range expr.Elts {
+			kv, _ := elt.(*ast.KeyValueExpr)
+			if kv == nil {
+				return ""
+			}
+			key, _ := kv.Key.(*ast.Ident)
+			if key == nil {
+				continue
+			}
+			val := f.pass.TypesInfo.Types[kv.Value].Value
+			if val != nil && val.Kind() == constant.Int && constant.Sign(val) < 0 && containsString(kind.timeouts, key.Name) {
+				return "with " + kind.name + " options that set a negative " + key.Name
+			}
+			// Anything but a constant zero is considered set
+			if containsString(kind.required, key.Name) && (val == nil || constant.Sign(val) != 0) {
+				satisfied = true
+			}
+		}
+	case *ast.Ident:
+		v, _ := f.pass.TypesInfo.ObjectOf(expr).(*types.Var)
+		if v == nil || f.vars[v] || f.params[v] || f.resolver.rangeVars[v] != nil {
+			return ""
+		}
+		f.vars[v] = true
+		defer delete(f.vars, v)
+		for _, field := range kind.required {
+			satisfied = satisfied || f.fieldWrites[v][field]
+		}
+		// Without any values, the var has the zero value
+		for _, value := range f.resolver.varValues[v] {
+			if problem := f.optionsProblem(value, kind, satisfied); problem != "" {
+				return problem
+			}
+		}
+		if len(f.resolver.varValues[v]) > 0 {
+			return ""
+		}
+	default:
+		return ""
+	}
+	if !satisfied && len(kind.required) > 0 {
+		return "with " + kind.name + " options that set neither " + strings.Join(kind.required, " nor ")
+	}
+	return ""
+}
+
+// reachingValues returns the values assigned to the var before the given
+// position that may reach it, and whether the value the var had before any of
+// these assignments may reach it. Assignments in a block not containing the
+// position are assumed to only possibly reach it.
+func (f *optionFinder) reachingValues(v *types.Var, pos token.Pos) (values []ast.Expr, initial bool) {
+	var before []ast.Expr
+	for _, value := range f.resolver.varValues[v] {
+		if value.End() <= pos {
+			before = append(before, value)
+		}
+	}
+	// Walk from the latest assignment until one that always reaches
+	for i := len(before) - 1; i >= 0; i-- {
+		values = append(values, before[i])
+		if f.enclosingBlockContains(before[i], pos) {
+			return values, false
+		}
+	}
+	return values, true
+}
+
+// enclosingBlockContains returns true if the innermost block containing the
+// given node also contains the given position.
+func (f *optionFinder) enclosingBlockContains(node ast.Node, pos token.Pos) bool {
+	for _, file := range f.pass.Files {
+		if node.Pos() < file.Pos() || node.End() > file.End() {
+			continue
+		}
+		path, _ := astutil.PathEnclosingInterval(file, node.Pos(), node.End())
+		for _, n := range path {
+			switch n.(type) {
+			case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+				return n.Pos() <= pos && pos < n.End()
+			}
+		}
+	}
+	return false
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package workflow
+
+import (
+	"go/ast"
+	"log"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkerDebug is the debug logging of a checker. Checker shares its own with
+// every checker it runs.
+type checkerDebug struct {
+	DebugfFunc func(string, ...interface{})
+	Debug      bool
+}
+
+// newCheckerDebug creates a checkerDebug using log.Printf if debugfFunc is nil.
+func newCheckerDebug(debugfFunc func(string, ...interface{}), debug bool) *checkerDebug {
+	if debugfFunc == nil {
+		debugfFunc = log.Printf
+	}
+	return &checkerDebug{DebugfFunc: debugfFunc, Debug: debug}
+}
+
+func (c *checkerDebug) debugf(f string, v ...interface{}) {
+	if c.Debug {
+		c.DebugfFunc(f, v...)
+	}
+}
+
+// checkedPackage is the package of a pass resolved once and shared by every
+// checker run on the pass.
+type checkedPackage struct {
+	pass     *analysis.Pass
+	resolver *rootResolver
+	// Workflow functions found by findRoots
+	roots []*root
+	// Registration arguments whose function could not be determined
+	unresolved []ast.Expr
+	// Activity functions found by findActivities, only set once needed
+	activities      []*root
+	activitiesFound bool
+}
+
+// newCheckedPackage resolves the workflows of the package in the pass, also
+// checking exported functions that look like workflows if bySignature is true.
+func newCheckedPackage(pass *analysis.Pass, bySignature bool) *checkedPackage {
+	p := &checkedPackage{pass: pass, resolver: newRootResolver(pass)}
+	p.resolver.exportVarFuncs()
+	p.roots, p.unresolved = findRoots(p.resolver, bySignature)
+	return p
+}
+
+// registeredActivities returns the activity functions registered in the
+// package.
+func (p *checkedPackage) registeredActivities() []*root {
+	if !p.activitiesFound {
+		p.activities, p.activitiesFound = findActivities(p.resolver), true
+	}
+	return p.activities
+}
+
+// rootFact sets fact to the object fact of the root's function. Function
+// literals have no facts, so litFact is called for them instead if non-nil.
+// Facts for functions in this package must already be exported.
+func (p *checkedPackage) rootFact(r *root, fact analysis.Fact, litFact func(*ast.FuncLit)) {
+	if r.lit != nil {
+		if litFact != nil {
+			litFact(r.lit)
+		}
+	} else {
+		p.pass.ImportObjectFact(r.fn, fact)
+	}
+}
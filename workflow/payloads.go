@@ -0,0 +1,314 @@
+package workflow
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// payloadQueryArgs are the qualified names of functions that set query
+// handlers with the index of the handler argument.
+var payloadQueryArgs = map[string]int{
+	"go.temporal.io/sdk/workflow.SetQueryHandler": 2,
+	"go.temporal.io/sdk/internal.SetQueryHandler": 2,
+}
+
+// payloadSignalArgs are the qualified names of functions that send signals
+// with the index of the signal value argument.
+var payloadSignalArgs = map[string]int{
+	"go.temporal.io/sdk/workflow.SignalExternalWorkflow":           4,
+	"go.temporal.io/sdk/internal.SignalExternalWorkflow":           4,
+	"(go.temporal.io/sdk/client.Client).SignalWorkflow":            4,
+	"(go.temporal.io/sdk/internal.Client).SignalWorkflow":          4,
+	"(go.temporal.io/sdk/client.Client).SignalWithStartWorkflow":   3,
+	"(go.temporal.io/sdk/internal.Client).SignalWithStartWorkflow": 3,
+}
+
+// payloadReceiveArgs are the qualified names of functions that receive from
+// channels with the index of the value pointer argument. These are only
+// checked when the channel is a signal channel.
+var payloadReceiveArgs = map[string]int{
+	"(go.temporal.io/sdk/workflow.ReceiveChannel).Receive":      1,
+	"(go.temporal.io/sdk/workflow.ReceiveChannel).ReceiveAsync": 0,
+	"(go.temporal.io/sdk/internal.ReceiveChannel).Receive":      1,
+	"(go.temporal.io/sdk/internal.ReceiveChannel).ReceiveAsync": 0,
+}
+
+// signalChannelFuncs are the qualified names of functions that return signal
+// channels.
+var signalChannelFuncs = map[string]bool{
+	"go.temporal.io/sdk/workflow.GetSignalChannel": true,
+	"go.temporal.io/sdk/internal.GetSignalChannel": true,
+}
+
+// PayloadConfig is config for NewPayloadChecker.
+type PayloadConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// PayloadChecker checks that the parameters and results of workflows,
+// activities, signals, and queries can be serialized by the default data
+// converter.
+type PayloadChecker struct {
+	*checkerDebug
+}
+
+// NewPayloadChecker creates a PayloadChecker for the given config.
+func NewPayloadChecker(config PayloadConfig) *PayloadChecker {
+	// Build checker
+	return &PayloadChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is a -payload-debug flag for enabling debug logs. This analyzer
+// does not have any results but does set *VarFuncs facts on package-level vars.
+func (c *PayloadChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowpayloads",
+		Doc:       "Analyzes workflow, activity, signal, and query payload types for serializability",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "payload-debug", c.Debug, "show payload debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *PayloadChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *PayloadChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	c.debugf("Checking payloads of package %v", pass.Pkg.Path())
+	// Check every workflow except interceptors and dynamic workflows which
+	// take raw payloads
+	for _, root := range p.roots {
+		if sig := root.signature(pass); sig != nil && !root.interceptor && !root.dynamic {
+			c.checkFunc(pass, root.pos, "workflow "+root.subject(), sig)
+		}
+	}
+	// Check every activity
+	for _, activity := range p.registeredActivities() {
+		if sig := activity.signature(pass); sig != nil {
+			c.checkFunc(pass, activity.pos, "activity "+activity.name, sig)
+		}
+	}
+	// Check queries and signals
+	r := p.resolver
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			callExpr, _ := n.(*ast.CallExpr)
+			if callExpr == nil {
+				return true
+			}
+			callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+			if callee == nil {
+				return true
+			}
+			argIndex := -1
+			if index, ok := payloadQueryArgs[callee.FullName()]; ok && index < len(callExpr.Args) {
+				for _, handler := range r.resolve(callExpr.Pos(), callExpr.Args[index]) {
+					if sig := handler.signature(pass); sig != nil {
+						c.checkFunc(pass, callExpr.Pos(), "query handler "+handler.name, sig)
+					}
+				}
+			} else if index, ok := payloadSignalArgs[callee.FullName()]; ok {
+				argIndex = index
+			} else if index, ok := payloadReceiveArgs[callee.FullName()]; ok && isSignalChannel(pass, r, callExpr.Fun) {
+				argIndex = index
+			}
+			if argIndex >= 0 && argIndex < len(callExpr.Args) {
+				t := pass.TypesInfo.TypeOf(callExpr.Args[argIndex])
+				// Received values are given as pointers
+				if _, ok := payloadReceiveArgs[callee.FullName()]; ok {
+					ptr, _ := t.(*types.Pointer)
+					if ptr == nil {
+						return true
+					}
+					t = ptr.Elem()
+				}
+				c.checkType(pass, callExpr.Pos(), "signal value", t, "value")
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// checkFunc checks the params and results of the given function except for a
+// leading context and trailing error.
+func (c *PayloadChecker) checkFunc(pass *analysis.Pass, pos token.Pos, subject string, sig *types.Signature) {
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		if i == 0 && (isWorkflowContext(param.Type()) || isContext(param.Type())) {
+			continue
+		}
+		name := param.Name()
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("arg%v", i)
+		}
+		c.checkType(pass, pos, subject+" param "+name, param.Type(), name)
+	}
+	results := sig.Results()
+	for i := 0; i < results.Len(); i++ {
+		if i == results.Len()-1 && isError(results.At(i).Type()) {
+			continue
+		}
+		name := "result"
+		if results.Len() > 2 {
+			name = fmt.Sprintf("result%v", i)
+		}
+		c.checkType(pass, pos, subject+" "+name, results.At(i).Type(), name)
+	}
+}
+
+// checkType reports every unserializable part of the given type.
+func (c *PayloadChecker) checkType(pass *analysis.Pass, pos token.Pos, subject string, t types.Type, path string) {
+	qual := types.RelativeTo(pass.Pkg)
+	typeProblems(t, path, map[*types.Named]bool{}, func(path string, problem string) {
+		c.debugf("Type %v of %v unserializable at %v: %v", types.TypeString(t, qual), subject, path, problem)
+		pass.Reportf(pos, "%v has unserializable type at %v: %v", subject, path, problem)
+	}, qual)
+}
+
+// typeProblems calls report with the path and problem of every part of the
+// type that cannot be serialized by the default data converter. Named types
+// already being visited are skipped to prevent recursion.
+func typeProblems(
+	t types.Type,
+	path string,
+	visiting map[*types.Named]bool,
+	report func(path string, problem string),
+	qual types.Qualifier,
+) {
+	if named, _ := t.(*types.Named); named != nil {
+		if visiting[named] || customSerialized(named) {
+			return
+		}
+		visiting[named] = true
+		defer delete(visiting, named)
+	}
+	typeStr := types.TypeString(t, qual)
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		if u.Info()&types.IsComplex != 0 {
+			report(path, typeStr+" is a complex number")
+		} else if u.Kind() == types.UnsafePointer {
+			report(path, typeStr+" is an unsafe pointer")
+		}
+	case *types.Chan:
+		report(path, typeStr+" is a channel")
+	case *types.Signature:
+		report(path, typeStr+" is a function")
+	case *types.Interface:
+		report(path, typeStr+" is an interface without a concrete type")
+	case *types.Pointer:
+		typeProblems(u.Elem(), path, visiting, report, qual)
+	case *types.Slice:
+		typeProblems(u.Elem(), path+"[]", visiting, report, qual)
+	case *types.Array:
+		typeProblems(u.Elem(), path+"[]", visiting, report, qual)
+	case *types.Map:
+		if !supportedMapKey(u.Key()) {
+			report(path, typeStr+" has unsupported map key type "+types.TypeString(u.Key(), qual))
+		}
+		typeProblems(u.Elem(), path+"[]", visiting, report, qual)
+	case *types.Struct:
+		serialized := 0
+		for i := 0; i < u.NumFields(); i++ {
+			field := u.Field(i)
+			if !field.Exported() && !field.Embedded() {
+				continue
+			}
+			serialized++
+			if jsonName(u.Tag(i)) == "-" {
+				continue
+			}
+			typeProblems(field.Type(), path+"."+field.Name(), visiting, report, qual)
+		}
+		if u.NumFields() > 0 && serialized == 0 {
+			report(path, typeStr+" has only unexported fields")
+		}
+	}
+}
+
+// customSerialized returns true if the named type is from the SDK or API or
+// has its own JSON, text, or protobuf serialization.
+func customSerialized(named *types.Named) bool {
+	if pkg := named.Obj().Pkg(); pkg != nil && strings.HasPrefix(pkg.Path(), "go.temporal.io/") {
+		return true
+	}
+	methods := types.NewMethodSet(types.NewPointer(named))
+	for _, name := range []string{"MarshalJSON", "MarshalText", "ProtoReflect"} {
+		if methods.Lookup(nil, name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// supportedMapKey returns true if the type can be a JSON object key, which is
+// a string, an integer, or a type with its own text serialization.
+func supportedMapKey(t types.Type) bool {
+	if basic, _ := t.Underlying().(*types.Basic); basic != nil && basic.Info()&(types.IsString|types.IsInteger) != 0 {
+		return true
+	}
+	return types.NewMethodSet(types.NewPointer(t)).Lookup(nil, "MarshalText") != nil
+}
+
+// jsonName returns the name portion of the json struct tag.
+func jsonName(tag string) string {
+	name := reflect.StructTag(tag).Get("json")
+	if comma := strings.Index(name, ","); comma >= 0 {
+		name = name[:comma]
+	}
+	return name
+}
+
+// isContext returns true if the type is the standard library context.
+func isContext(t types.Type) bool {
+	named, _ := t.(*types.Named)
+	return named != nil && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" &&
+		named.Obj().Name() == "Context"
+}
+
+// isSignalChannel returns true if the receiver of the given method selector is
+// a call to get a signal channel or a var only assigned from such calls.
+func isSignalChannel(pass *analysis.Pass, r *rootResolver, fun ast.Expr) bool {
+	sel, _ := fun.(*ast.SelectorExpr)
+	if sel == nil {
+		return false
+	}
+	var values []ast.Expr
+	if ident := calleeIdent(sel.X); ident != nil {
+		if v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var); v != nil {
+			values = r.varValues[v]
+		}
+	} else {
+		values = []ast.Expr{sel.X}
+	}
+	for _, value := range values {
+		callExpr, _ := value.(*ast.CallExpr)
+		if callExpr == nil {
+			return false
+		}
+		callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+		if callee == nil || !signalChannelFuncs[callee.FullName()] {
+			return false
+		}
+	}
+	return len(values) > 0
+}
@@ -0,0 +1,303 @@
+package workflow
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Registration is a single workflow or activity registered on a worker.
+type Registration struct {
+	// Either "workflow" or "activity"
+	Kind string
+	// Workflow or activity type name
+	Name string
+	// Qualified name of the registered function
+	Func string
+	// Whether the registration has DisableAlreadyRegisteredCheck set
+	AllowDuplicate bool
+}
+
+// Registrations is the object fact of workflows and activities a function
+// registers on its parameters, directly or transitively. The key is the index
+// of the parameter.
+type Registrations map[int][]*Registration
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*Registrations) AFact() {}
+
+// String returns all registrations as a comma-delimited string sorted by
+// parameter index.
+func (r *Registrations) String() string {
+	if r == nil {
+		return "<none>"
+	}
+	indices := make([]int, 0, len(*r))
+	for index := range *r {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	var strs []string
+	for _, index := range indices {
+		for _, reg := range (*r)[index] {
+			strs = append(strs, fmt.Sprintf("param %v %v %v (%v)", index, reg.Kind, reg.Name, reg.Func))
+		}
+	}
+	return strings.Join(strs, ", ")
+}
+
+// registrationCalls are the qualified names of functions that register
+// workflows or activities with the kind registered and the index of the
+// options argument or -1 if none.
+var registrationCalls = map[string]struct {
+	kind      string
+	optsIndex int
+}{
+	"(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterWorkflow":            {"workflow", -1},
+	"(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterWorkflowWithOptions": {"workflow", 1},
+	"(go.temporal.io/sdk/worker.ActivityRegistry).RegisterActivity":            {"activity", -1},
+	"(go.temporal.io/sdk/worker.ActivityRegistry).RegisterActivityWithOptions": {"activity", 1},
+}
+
+// RegistrationConfig is config for NewRegistrationChecker.
+type RegistrationConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// RegistrationChecker checks that workflows and activities registered on the
+// same worker do not have conflicting type names and are not registered more
+// than once.
+type RegistrationChecker struct {
+	*checkerDebug
+}
+
+// NewRegistrationChecker creates a RegistrationChecker for the given config.
+func NewRegistrationChecker(config RegistrationConfig) *RegistrationChecker {
+	// Build checker
+	return &RegistrationChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is a -registration-debug flag for enabling debug logs. This
+// analyzer does not have any results but does set *Registrations facts on
+// functions and *VarFuncs facts on package-level vars.
+func (c *RegistrationChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowregistrations",
+		Doc:       "Analyzes workflow and activity registrations for conflicting names",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&Registrations{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "registration-debug", c.Debug, "show registration debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *RegistrationChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *RegistrationChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	c.debugf("Checking registrations of package %v", pass.Pkg.Path())
+	w := &registrationWalker{
+		RegistrationChecker: c,
+		pass:                pass,
+		resolver:            p.resolver,
+		results:             map[*types.Func]Registrations{},
+		walking:             map[*types.Func]bool{},
+	}
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if funcDecl, _ := decl.(*ast.FuncDecl); funcDecl != nil {
+				if fn, _ := pass.TypesInfo.ObjectOf(funcDecl.Name).(*types.Func); fn != nil {
+					w.funcRegistrations(fn)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type registrationWalker struct {
+	*RegistrationChecker
+	pass     *analysis.Pass
+	resolver *rootResolver
+	// Registrations on params of functions in this package already walked
+	results map[*types.Func]Registrations
+	// Functions currently being walked to prevent recursion
+	walking map[*types.Func]bool
+}
+
+// funcRegistrations returns the registrations on params of the given function
+// in this package, walking it and reporting conflicts if not already done.
+func (w *registrationWalker) funcRegistrations(fn *types.Func) Registrations {
+	if regs, ok := w.results[fn]; ok {
+		return regs
+	}
+	funcDecl := w.resolver.funcDecls[fn]
+	if funcDecl == nil || funcDecl.Body == nil || w.walking[fn] {
+		return nil
+	}
+	w.walking[fn] = true
+	defer delete(w.walking, fn)
+	// Collect registrations per worker var in order
+	workers := map[*types.Var][]*Registration{}
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		callExpr, _ := n.(*ast.CallExpr)
+		if callExpr == nil {
+			return true
+		}
+		callee, _ := typeutil.Callee(w.pass.TypesInfo, callExpr).(*types.Func)
+		if callee == nil {
+			return true
+		}
+		if call, ok := registrationCalls[callee.FullName()]; ok {
+			// Only registrations on a worker var
+			if sel, _ := callExpr.Fun.(*ast.SelectorExpr); sel != nil {
+				if worker := w.identVar(sel.X); worker != nil {
+					for _, reg := range w.callRegistrations(callExpr, call.kind, call.optsIndex) {
+						workers[worker] = w.addRegistrations(callExpr, worker, workers[worker], reg)
+					}
+				}
+			}
+			return true
+		}
+		// Add registrations of calls to functions given a worker var
+		var calleeRegs Registrations
+		if callee.Pkg() == w.pass.Pkg {
+			calleeRegs = w.funcRegistrations(callee)
+		} else {
+			w.pass.ImportObjectFact(callee, &calleeRegs)
+		}
+		indices := make([]int, 0, len(calleeRegs))
+		for index := range calleeRegs {
+			indices = append(indices, index)
+		}
+		sort.Ints(indices)
+		for _, index := range indices {
+			if index < len(callExpr.Args) {
+				if worker := w.identVar(callExpr.Args[index]); worker != nil {
+					workers[worker] = w.addRegistrations(callExpr, worker, workers[worker], calleeRegs[index]...)
+				}
+			}
+		}
+		return true
+	})
+	// Registrations on params become the result and fact
+	regs := Registrations{}
+	sig, _ := fn.Type().(*types.Signature)
+	for i := 0; sig != nil && i < sig.Params().Len(); i++ {
+		if paramRegs := workers[sig.Params().At(i)]; len(paramRegs) > 0 {
+			regs[i] = paramRegs
+		}
+	}
+	w.results[fn] = regs
+	if len(regs) > 0 {
+		w.pass.ExportObjectFact(fn, &regs)
+	}
+	return regs
+}
+
+// callRegistrations returns the registrations made by a direct registration
+// call. Function literals without an explicit name are ignored.
+func (w *registrationWalker) callRegistrations(callExpr *ast.CallExpr, kind string, optsIndex int) []*Registration {
+	if len(callExpr.Args) == 0 {
+		return nil
+	}
+	var name string
+	var allowDuplicate bool
+	if optsIndex >= 0 && optsIndex < len(callExpr.Args) {
+		name = w.resolver.registeredName(callExpr.Args[optsIndex])
+		if val := w.resolver.optionValue(callExpr.Args[optsIndex], "DisableAlreadyRegisteredCheck"); val != nil &&
+			val.Kind() == constant.Bool {
+			allowDuplicate = constant.BoolVal(val)
+		}
+	}
+	// Activity structs register every exported method with the name as a
+	// prefix
+	arg := callExpr.Args[0]
+	if _, isFunc := w.pass.TypesInfo.TypeOf(arg).Underlying().(*types.Signature); kind == "activity" && !isFunc {
+		var regs []*Registration
+		methods := types.NewMethodSet(w.pass.TypesInfo.TypeOf(arg))
+		for i := 0; i < methods.Len(); i++ {
+			if method, _ := methods.At(i).Obj().(*types.Func); method != nil && method.Exported() {
+				regs = append(regs, &Registration{
+					Kind:           kind,
+					Name:           name + method.Name(),
+					Func:           method.FullName(),
+					AllowDuplicate: allowDuplicate,
+				})
+			}
+		}
+		return regs
+	}
+	var regs []*Registration
+	for _, root := range w.resolver.resolve(callExpr.Pos(), arg) {
+		reg := &Registration{Kind: kind, Name: name, AllowDuplicate: allowDuplicate}
+		if root.fn != nil {
+			reg.Func = root.fn.FullName()
+			if reg.Name == "" {
+				reg.Name = root.fn.Name()
+			}
+		} else {
+			reg.Func = root.name
+		}
+		if reg.Name != "" {
+			regs = append(regs, reg)
+		}
+	}
+	return regs
+}
+
+// addRegistrations reports any of the given registrations that conflict with
+// the existing registrations on the worker and returns the combined
+// registrations.
+func (w *registrationWalker) addRegistrations(
+	callExpr *ast.CallExpr,
+	worker *types.Var,
+	existing []*Registration,
+	regs ...*Registration,
+) []*Registration {
+	for _, reg := range regs {
+		if reg.AllowDuplicate {
+			continue
+		}
+		for _, prev := range existing {
+			if prev.Kind != reg.Kind || prev.Name != reg.Name {
+				continue
+			}
+			if prev.Func == reg.Func {
+				w.pass.Reportf(callExpr.Pos(), "%v %v registered more than once on worker %v as type %q",
+					reg.Kind, reg.Func, worker.Name(), reg.Name)
+			} else {
+				w.pass.Reportf(callExpr.Pos(), "%v type %q registered on worker %v by %v conflicts with %v",
+					reg.Kind, reg.Name, worker.Name(), reg.Func, prev.Func)
+			}
+			break
+		}
+	}
+	return append(existing, regs...)
+}
+
+// identVar returns the var of the given expression if it is an identifier.
+func (w *registrationWalker) identVar(expr ast.Expr) *types.Var {
+	ident := calleeIdent(expr)
+	if ident == nil {
+		return nil
+	}
+	v, _ := w.pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	return v
+}
@@ -0,0 +1,736 @@
+package workflow
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// root is a workflow function found in the package being checked.
+type root struct {
+	// Position diagnostics for this root are reported at
+	pos token.Pos
+	// Name of the function for diagnostics
+	name string
+	// Workflow type name this root is registered with if known and it is not
+	// the default
+	typeName string
+	// Whether this root is registered as a dynamic workflow
+	dynamic bool
+	// Whether this root is an interceptor method instead of a workflow
+	interceptor bool
+	// Exactly one of these is set. Function literals are always in the package
+	// being checked.
+	fn  *types.Func
+	lit *ast.FuncLit
+}
+
+// pkg returns the package the root's function is defined in.
+func (r *root) pkg(pass *analysis.Pass) *types.Package {
+	if r.fn != nil {
+		return r.fn.Pkg()
+	}
+	return pass.Pkg
+}
+
+// signature returns the signature of the root's function.
+func (r *root) signature(pass *analysis.Pass) *types.Signature {
+	var sig *types.Signature
+	if r.fn != nil {
+		sig, _ = r.fn.Type().(*types.Signature)
+	} else {
+		sig, _ = pass.TypesInfo.TypeOf(r.lit).(*types.Signature)
+	}
+	return sig
+}
+
+// subject returns the subject of diagnostics for this root, which includes
+// the registered workflow type name if known.
+func (r *root) subject() string {
+	if r.dynamic {
+		return "dynamic workflow (" + r.name + ")"
+	} else if r.typeName != "" {
+		return r.typeName + " (" + r.name + ")"
+	}
+	return r.name
+}
+
+// WorkflowDirective is the comment directive that can be placed in the doc of a
+// function to mark it as a workflow.
+const WorkflowDirective = "//temporal:workflow"
+
+// registrationArgs are the qualified names of functions that register
+// workflows with the index of the workflow argument.
+var registrationArgs = map[string]int{
+	"(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterWorkflow":            0,
+	"(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterWorkflowWithOptions": 0,
+	"(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterDynamicWorkflow":     0,
+}
+
+// activityRegistrationArgs are the qualified names of functions that register
+// activities with the index of the activity argument.
+var activityRegistrationArgs = map[string]int{
+	"(go.temporal.io/sdk/worker.ActivityRegistry).RegisterActivity":            0,
+	"(go.temporal.io/sdk/worker.ActivityRegistry).RegisterActivityWithOptions": 0,
+}
+
+// registrationOptionsArgs are the qualified names of functions that register
+// workflows with options containing the workflow type name with the index of
+// the options argument.
+var registrationOptionsArgs = map[string]int{
+	"(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterWorkflowWithOptions": 1,
+}
+
+const dynamicRegistrationName = "(go.temporal.io/sdk/worker.WorkflowRegistry).RegisterDynamicWorkflow"
+
+// executionArgs are the qualified names of functions that execute workflows
+// with the index of the workflow argument. Some are present with the internal
+// name too since the public types are aliases.
+var executionArgs = map[string]int{
+	"go.temporal.io/sdk/workflow.ExecuteChildWorkflow":                        1,
+	"(go.temporal.io/sdk/client.Client).ExecuteWorkflow":                      2,
+	"(go.temporal.io/sdk/internal.Client).ExecuteWorkflow":                    2,
+	"(go.temporal.io/sdk/client.Client).SignalWithStartWorkflow":              5,
+	"(go.temporal.io/sdk/internal.Client).SignalWithStartWorkflow":            5,
+	"(*go.temporal.io/sdk/testsuite.TestWorkflowEnvironment).ExecuteWorkflow": 0,
+	"(*go.temporal.io/sdk/internal.TestWorkflowEnvironment).ExecuteWorkflow":  0,
+}
+
+// findRoots returns all workflow functions registered in the package. Each
+// registration argument whose function could not be determined is returned in
+// unresolved. Functions executed as workflows (e.g. child workflows), methods
+// of workflow interceptors, functions with WorkflowDirective, and if
+// bySignature is true exported functions that look like workflows, are also
+// returned once each if they are not otherwise registered in the package.
+func findRoots(r *rootResolver, bySignature bool) (roots []*root, unresolved []ast.Expr) {
+	pass := r.pass
+	var execRoots []*root
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			// Only handle calls
+			callExpr, _ := n.(*ast.CallExpr)
+			if callExpr == nil {
+				return true
+			}
+			// Callee needs to be a registration or execution
+			callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+			if callee == nil {
+				return true
+			}
+			if argIndex, ok := registrationArgs[callee.FullName()]; ok && argIndex < len(callExpr.Args) {
+				if argRoots := r.resolve(callExpr.Pos(), callExpr.Args[argIndex]); argRoots == nil {
+					unresolved = append(unresolved, callExpr.Args[argIndex])
+				} else {
+					var typeName string
+					if optsIndex, ok := registrationOptionsArgs[callee.FullName()]; ok && optsIndex < len(callExpr.Args) {
+						typeName = r.registeredName(callExpr.Args[optsIndex])
+					}
+					for _, root := range argRoots {
+						root.typeName = typeName
+						root.dynamic = callee.FullName() == dynamicRegistrationName
+					}
+					roots = append(roots, argRoots...)
+				}
+			} else if argIndex, ok := executionArgs[callee.FullName()]; ok && argIndex < len(callExpr.Args) {
+				// Executions by name or with unknown functions are ignored
+				execRoots = append(execRoots, r.resolve(callExpr.Pos(), callExpr.Args[argIndex])...)
+			}
+			return true
+		})
+	}
+	// Add executed workflows that are not already registered
+	seen := map[*types.Func]bool{}
+	for _, root := range roots {
+		if root.fn != nil {
+			seen[root.fn] = true
+		}
+	}
+	for _, root := range execRoots {
+		if root.fn == nil || !seen[root.fn] {
+			roots = append(roots, root)
+			if root.fn != nil {
+				seen[root.fn] = true
+			}
+		}
+	}
+	// Add interceptor methods that are not already found
+	for _, root := range interceptorRoots(pass) {
+		if !seen[root.fn] {
+			roots = append(roots, root)
+			seen[root.fn] = true
+		}
+	}
+	// Find functions declared as workflows that are not already found
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			funcDecl, _ := decl.(*ast.FuncDecl)
+			if funcDecl == nil {
+				continue
+			}
+			fn, _ := pass.TypesInfo.ObjectOf(funcDecl.Name).(*types.Func)
+			if fn == nil || seen[fn] {
+				continue
+			}
+			if hasDirective(funcDecl.Doc, WorkflowDirective) || (bySignature && fn.Exported() && looksLikeWorkflow(fn)) {
+				roots = append(roots, &root{pos: funcDecl.Name.Pos(), name: fn.FullName(), fn: fn})
+			}
+		}
+	}
+	return
+}
+
+// findActivities returns all activity functions registered in the package. A
+// registered struct registers every exported method in its method set, and a
+// registered interface value (e.g. ranging over a []interface{}) is traced
+// back to the functions and structs it holds. The position of each is the
+// registration call.
+func findActivities(r *rootResolver) (activities []*root) {
+	pass := r.pass
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			callExpr, _ := n.(*ast.CallExpr)
+			if callExpr == nil {
+				return true
+			}
+			callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+			if callee == nil {
+				return true
+			}
+			argIndex, ok := activityRegistrationArgs[callee.FullName()]
+			if !ok || argIndex >= len(callExpr.Args) {
+				return true
+			}
+			activities = append(activities, r.resolveActivity(callExpr.Pos(), callExpr.Args[argIndex])...)
+			return true
+		})
+	}
+	return
+}
+
+// interceptorInterfaces are the names of the interfaces in the interceptor
+// package whose implementations run as workflow code.
+var interceptorInterfaces = []string{"WorkflowInboundInterceptor", "WorkflowOutboundInterceptor"}
+
+// interceptorRoots returns the methods declared in the package that implement
+// a method of a workflow interceptor interface. The interceptor interfaces are
+// only found if the package imports the interceptor package (or the internal
+// package they alias).
+func interceptorRoots(pass *analysis.Pass) (roots []*root) {
+	var ifaces []*types.Interface
+	for _, imp := range pass.Pkg.Imports() {
+		if imp.Path() != "go.temporal.io/sdk/interceptor" && imp.Path() != "go.temporal.io/sdk/internal" {
+			continue
+		}
+		for _, name := range interceptorInterfaces {
+			if typeName, _ := imp.Scope().Lookup(name).(*types.TypeName); typeName != nil {
+				if iface, _ := typeName.Type().Underlying().(*types.Interface); iface != nil {
+					ifaces = append(ifaces, iface)
+				}
+			}
+		}
+	}
+	if len(ifaces) == 0 {
+		return nil
+	}
+	seen := map[*types.Func]bool{}
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		typeName, _ := scope.Lookup(name).(*types.TypeName)
+		if typeName == nil || typeName.IsAlias() {
+			continue
+		}
+		if _, isIface := typeName.Type().Underlying().(*types.Interface); isIface {
+			continue
+		}
+		ptr := types.NewPointer(typeName.Type())
+		methods := types.NewMethodSet(ptr)
+		for _, iface := range ifaces {
+			if !types.Implements(ptr, iface) {
+				continue
+			}
+			// Only methods declared in this package, not ones promoted from
+			// the embedded base types
+			for i := 0; i < iface.NumMethods(); i++ {
+				sel := methods.Lookup(iface.Method(i).Pkg(), iface.Method(i).Name())
+				if sel == nil {
+					continue
+				}
+				fn, _ := sel.Obj().(*types.Func)
+				if fn != nil && fn.Pkg() == pass.Pkg && !seen[fn] {
+					seen[fn] = true
+					roots = append(roots, &root{pos: fn.Pos(), name: fn.FullName(), fn: fn, interceptor: true})
+				}
+			}
+		}
+	}
+	return
+}
+
+func hasDirective(doc *ast.CommentGroup, directive string) bool {
+	if doc != nil {
+		for _, comment := range doc.List {
+			if comment.Text == directive {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// looksLikeWorkflow returns true if the first parameter of the function is a
+// workflow context and the last result is an error.
+func looksLikeWorkflow(fn *types.Func) bool {
+	sig, _ := fn.Type().(*types.Signature)
+	return takesWorkflowContext(fn) && sig.Results().Len() > 0 && isError(sig.Results().At(sig.Results().Len()-1).Type())
+}
+
+// takesWorkflowContext returns true if the first parameter of the function is
+// a workflow context.
+func takesWorkflowContext(fn *types.Func) bool {
+	sig, _ := fn.Type().(*types.Signature)
+	return sig != nil && sig.Params().Len() > 0 && isWorkflowContext(sig.Params().At(0).Type())
+}
+
+// isWorkflowContext returns true if the type is the workflow context, which
+// may be an alias of the internal context.
+func isWorkflowContext(t types.Type) bool {
+	named, _ := t.(*types.Named)
+	if named == nil || named.Obj().Pkg() == nil || named.Obj().Name() != "Context" {
+		return false
+	}
+	path := named.Obj().Pkg().Path()
+	return path == "go.temporal.io/sdk/workflow" || path == "go.temporal.io/sdk/internal"
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// VarFuncs is the object fact of the functions held by an exported
+// package-level var of a function type, or by its elements if it is a slice,
+// array, or map, so registrations in other packages can be resolved through
+// it. It is only set if every value assigned to the var in its package is a
+// known function.
+type VarFuncs []*types.Func
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*VarFuncs) AFact() {}
+
+// String returns all function names as a comma-delimited string.
+func (v *VarFuncs) String() string {
+	if v == nil {
+		return "<none>"
+	}
+	names := make([]string, len(*v))
+	for i, fn := range *v {
+		names[i] = fn.FullName()
+	}
+	return strings.Join(names, ", ")
+}
+
+type rootResolver struct {
+	pass      *analysis.Pass
+	funcDecls map[*types.Func]*ast.FuncDecl
+	// All expressions assigned to each var in the package
+	varValues map[*types.Var][]ast.Expr
+	// Expression ranged over for each var that is the value of a range
+	rangeVars map[*types.Var]ast.Expr
+	// Factories and vars currently being resolved to prevent recursion
+	factories map[*types.Func]bool
+	vars      map[*types.Var]bool
+}
+
+func newRootResolver(pass *analysis.Pass) *rootResolver {
+	r := &rootResolver{
+		pass:      pass,
+		funcDecls: map[*types.Func]*ast.FuncDecl{},
+		varValues: map[*types.Var][]ast.Expr{},
+		rangeVars: map[*types.Var]ast.Expr{},
+		factories: map[*types.Func]bool{},
+		vars:      map[*types.Var]bool{},
+	}
+	// Collect top-level funcs and everything assigned to vars
+	addVarValue := func(ident ast.Expr, value ast.Expr) {
+		if ident, _ := ident.(*ast.Ident); ident != nil {
+			if v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var); v != nil {
+				r.varValues[v] = append(r.varValues[v], value)
+			}
+		}
+	}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if fn, _ := pass.TypesInfo.ObjectOf(n.Name).(*types.Func); fn != nil {
+					r.funcDecls[fn] = n
+				}
+			case *ast.AssignStmt:
+				// Multi-value assignments from a single call are not tracked
+				if len(n.Lhs) == len(n.Rhs) {
+					for i, lhs := range n.Lhs {
+						addVarValue(lhs, n.Rhs[i])
+					}
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) == len(n.Values) {
+					for i, name := range n.Names {
+						addVarValue(name, n.Values[i])
+					}
+				}
+			case *ast.RangeStmt:
+				if ident, _ := n.Value.(*ast.Ident); ident != nil {
+					if v, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var); v != nil {
+						r.rangeVars[v] = n.X
+					}
+				}
+			}
+			return true
+		})
+	}
+	return r
+}
+
+// resolve returns the roots for the given expression referencing a workflow
+// function or nil if they cannot be determined. The result may be empty but
+// non-nil if the expression is already being resolved.
+func (r *rootResolver) resolve(pos token.Pos, expr ast.Expr) []*root {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		switch obj := r.pass.TypesInfo.ObjectOf(expr).(type) {
+		case *types.Func:
+			return []*root{{pos: pos, name: obj.FullName(), fn: obj}}
+		case *types.Var:
+			// A range value is each element of what is ranged over, otherwise it
+			// is every value assigned to the var
+			if rangeExpr := r.rangeVars[obj]; rangeExpr != nil {
+				return r.resolveElements(pos, rangeExpr, r.resolve)
+			}
+			return r.resolveVar(pos, obj, r.resolve)
+		}
+	case *ast.IndexExpr:
+		// Only the indexed element if the index is constant
+		if index := r.pass.TypesInfo.Types[expr.Index].Value; index != nil {
+			if elt := r.indexedElement(expr.X, index); elt != nil {
+				return r.resolve(pos, elt)
+			}
+		}
+		return r.resolveElements(pos, expr.X, r.resolve)
+	case *ast.SelectorExpr:
+		switch obj := r.pass.TypesInfo.ObjectOf(expr.Sel).(type) {
+		case *types.Func:
+			return []*root{{pos: pos, name: obj.FullName(), fn: obj}}
+		case *types.Var:
+			return r.resolveImportedVar(pos, obj)
+		}
+	case *ast.FuncLit:
+		return []*root{{pos: pos, name: "func literal", lit: expr}}
+	case *ast.CallExpr:
+		// A factory function in this package returning a function
+		factory, _ := typeutil.Callee(r.pass.TypesInfo, expr).(*types.Func)
+		if factory == nil || r.funcDecls[factory] == nil || r.funcDecls[factory].Body == nil || r.factories[factory] {
+			return nil
+		}
+		r.factories[factory] = true
+		defer delete(r.factories, factory)
+		var roots []*root
+		ok := true
+		ast.Inspect(r.funcDecls[factory].Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				// Returns in nested function literals are not the factory's
+				return false
+			case *ast.ReturnStmt:
+				if len(n.Results) != 1 {
+					ok = false
+				} else if lit, _ := n.Results[0].(*ast.FuncLit); lit != nil {
+					roots = append(roots, &root{pos: pos, name: "func literal returned from " + factory.FullName(), lit: lit})
+				} else if returned := r.resolve(pos, n.Results[0]); returned != nil {
+					roots = append(roots, returned...)
+				} else {
+					ok = false
+				}
+			}
+			return ok
+		})
+		if ok {
+			return roots
+		}
+	case *ast.ParenExpr:
+		return r.resolve(pos, expr.X)
+	}
+	return nil
+}
+
+// resolveActivity returns the activity functions for the given registered
+// activity expression or nil if they cannot be determined. Functions are
+// resolved like workflows and other values register every exported method in
+// their method set. Interface values are traced back to the values they hold.
+func (r *rootResolver) resolveActivity(pos token.Pos, expr ast.Expr) []*root {
+	t := r.pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return nil
+	} else if _, isFunc := t.Underlying().(*types.Signature); isFunc {
+		return r.resolve(pos, expr)
+	} else if _, isIface := t.Underlying().(*types.Interface); !isIface {
+		roots := []*root{}
+		methods := types.NewMethodSet(t)
+		for i := 0; i < methods.Len(); i++ {
+			if method, _ := methods.At(i).Obj().(*types.Func); method != nil && method.Exported() {
+				roots = append(roots, &root{pos: pos, name: method.FullName(), fn: method})
+			}
+		}
+		return roots
+	}
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if v, _ := r.pass.TypesInfo.ObjectOf(expr).(*types.Var); v != nil {
+			if rangeExpr := r.rangeVars[v]; rangeExpr != nil {
+				return r.resolveElements(pos, rangeExpr, r.resolveActivity)
+			}
+			return r.resolveVar(pos, v, r.resolveActivity)
+		}
+	case *ast.IndexExpr:
+		if index := r.pass.TypesInfo.Types[expr.Index].Value; index != nil {
+			if elt := r.indexedElement(expr.X, index); elt != nil {
+				return r.resolveActivity(pos, elt)
+			}
+		}
+		return r.resolveElements(pos, expr.X, r.resolveActivity)
+	case *ast.SelectorExpr:
+		if v, _ := r.pass.TypesInfo.ObjectOf(expr.Sel).(*types.Var); v != nil {
+			return r.resolveImportedVar(pos, v)
+		}
+	case *ast.ParenExpr:
+		return r.resolveActivity(pos, expr.X)
+	}
+	return nil
+}
+
+// resolveElements returns the roots from applying the given resolve function to
+// every element of the given expression of a slice, array, or map or nil if
+// any cannot be determined. Like resolve, the result may be empty but non-nil.
+func (r *rootResolver) resolveElements(
+	pos token.Pos,
+	expr ast.Expr,
+	resolve func(token.Pos, ast.Expr) []*root,
+) []*root {
+	switch expr := expr.(type) {
+	case *ast.CompositeLit:
+		roots := []*root{}
+		for _, elt := range expr.Elts {
+			if keyValue, _ := elt.(*ast.KeyValueExpr); keyValue != nil {
+				elt = keyValue.Value
+			}
+			eltRoots := resolve(pos, elt)
+			if eltRoots == nil {
+				return nil
+			}
+			roots = append(roots, eltRoots...)
+		}
+		return roots
+	case *ast.Ident:
+		if v, _ := r.pass.TypesInfo.ObjectOf(expr).(*types.Var); v != nil {
+			return r.resolveVar(pos, v, func(pos token.Pos, expr ast.Expr) []*root {
+				return r.resolveElements(pos, expr, resolve)
+			})
+		}
+	case *ast.SelectorExpr:
+		if v, _ := r.pass.TypesInfo.ObjectOf(expr.Sel).(*types.Var); v != nil {
+			return r.resolveImportedVar(pos, v)
+		}
+	case *ast.CallExpr:
+		// Only the append builtin is supported
+		if builtin, _ := r.pass.TypesInfo.Uses[calleeIdent(expr.Fun)].(*types.Builtin); builtin == nil ||
+			builtin.Name() != "append" || len(expr.Args) == 0 || expr.Ellipsis.IsValid() {
+			return nil
+		}
+		roots := r.resolveElements(pos, expr.Args[0], resolve)
+		if roots == nil {
+			return nil
+		}
+		for _, arg := range expr.Args[1:] {
+			argRoots := resolve(pos, arg)
+			if argRoots == nil {
+				return nil
+			}
+			roots = append(roots, argRoots...)
+		}
+		return roots
+	case *ast.ParenExpr:
+		return r.resolveElements(pos, expr.X, resolve)
+	}
+	return nil
+}
+
+// indexedElement returns the element expression at the given constant index
+// or key of the given slice, array, or map expression or nil if it cannot be
+// determined. Only composite literals, directly or as the only value of a var,
+// are supported.
+func (r *rootResolver) indexedElement(expr ast.Expr, index constant.Value) ast.Expr {
+	switch expr := expr.(type) {
+	case *ast.CompositeLit:
+		t := r.pass.TypesInfo.TypeOf(expr)
+		if t == nil {
+			return nil
+		}
+		_, isMap := t.Underlying().(*types.Map)
+		next := constant.MakeInt64(0)
+		for _, elt := range expr.Elts {
+			key := next
+			if keyValue, _ := elt.(*ast.KeyValueExpr); keyValue != nil {
+				if key = r.pass.TypesInfo.Types[keyValue.Key].Value; key == nil {
+					return nil
+				}
+				elt = keyValue.Value
+			} else if isMap {
+				return nil
+			}
+			if key.Kind() != index.Kind() {
+				return nil
+			} else if constant.Compare(key, token.EQL, index) {
+				return elt
+			}
+			if !isMap {
+				next = constant.BinaryOp(key, token.ADD, constant.MakeInt64(1))
+			}
+		}
+	case *ast.Ident:
+		if v, _ := r.pass.TypesInfo.ObjectOf(expr).(*types.Var); v != nil && len(r.varValues[v]) == 1 {
+			return r.indexedElement(r.varValues[v][0], index)
+		}
+	case *ast.ParenExpr:
+		return r.indexedElement(expr.X, index)
+	}
+	return nil
+}
+
+// resolveVar returns the roots from applying the given resolve function to
+// every value assigned to the var or nil if any cannot be determined. If the
+// var is already being resolved (e.g. appending to itself), this returns an
+// empty non-nil slice.
+func (r *rootResolver) resolveVar(
+	pos token.Pos,
+	v *types.Var,
+	resolve func(token.Pos, ast.Expr) []*root,
+) []*root {
+	values := r.varValues[v]
+	if len(values) == 0 {
+		return nil
+	} else if r.vars[v] {
+		return []*root{}
+	}
+	r.vars[v] = true
+	defer delete(r.vars, v)
+	roots := []*root{}
+	for _, value := range values {
+		valueRoots := resolve(pos, value)
+		if valueRoots == nil {
+			return nil
+		}
+		roots = append(roots, valueRoots...)
+	}
+	return roots
+}
+
+// resolveImportedVar returns the roots for the functions held by the given
+// package-level var of another package from its VarFuncs fact or nil if they
+// cannot be determined.
+func (r *rootResolver) resolveImportedVar(pos token.Pos, v *types.Var) []*root {
+	var funcs VarFuncs
+	if v.IsField() || v.Pkg() == nil || v.Pkg() == r.pass.Pkg || !r.pass.ImportObjectFact(v, &funcs) {
+		return nil
+	}
+	roots := make([]*root, len(funcs))
+	for i, fn := range funcs {
+		roots[i] = &root{pos: pos, name: fn.FullName(), fn: fn}
+	}
+	return roots
+}
+
+// exportVarFuncs sets the VarFuncs fact on every exported package-level var
+// whose functions can be determined.
+func (r *rootResolver) exportVarFuncs() {
+	scope := r.pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		v, _ := scope.Lookup(name).(*types.Var)
+		if v == nil || !v.Exported() {
+			continue
+		}
+		var roots []*root
+		switch v.Type().Underlying().(type) {
+		case *types.Signature:
+			roots = r.resolveVar(v.Pos(), v, r.resolve)
+		case *types.Slice, *types.Array, *types.Map:
+			roots = r.resolveVar(v.Pos(), v, func(pos token.Pos, expr ast.Expr) []*root {
+				return r.resolveElements(pos, expr, r.resolve)
+			})
+		}
+		funcs := make(VarFuncs, 0, len(roots))
+		for _, root := range roots {
+			// Function literals cannot be checked from other packages
+			if root.fn == nil {
+				funcs = nil
+				break
+			}
+			funcs = append(funcs, root.fn)
+		}
+		if len(funcs) > 0 {
+			r.pass.ExportObjectFact(v, &funcs)
+		}
+	}
+}
+
+// registeredName returns the constant Name field of the given register options
+// expression or empty if it cannot be determined.
+func (r *rootResolver) registeredName(expr ast.Expr) string {
+	if val := r.optionValue(expr, "Name"); val != nil && val.Kind() == constant.String {
+		return constant.StringVal(val)
+	}
+	return ""
+}
+
+// optionValue returns the constant value of the given field of the given
+// options composite literal expression or nil if it cannot be determined.
+func (r *rootResolver) optionValue(expr ast.Expr, field string) constant.Value {
+	for {
+		paren, _ := expr.(*ast.ParenExpr)
+		if paren == nil {
+			break
+		}
+		expr = paren.X
+	}
+	switch expr := expr.(type) {
+	case *ast.CompositeLit:
+		for _, elt := range expr.Elts {
+			kv, _ := elt.(*ast.KeyValueExpr)
+			if kv == nil {
+				continue
+			}
+			if key, _ := kv.Key.(*ast.Ident); key != nil && key.Name == field {
+				return r.pass.TypesInfo.Types[kv.Value].Value
+			}
+		}
+	case *ast.Ident:
+		// Only vars with a single value
+		if v, _ := r.pass.TypesInfo.ObjectOf(expr).(*types.Var); v != nil && len(r.varValues[v]) == 1 {
+			return r.optionValue(r.varValues[v][0], field)
+		}
+	}
+	return nil
+}
+
+func calleeIdent(expr ast.Expr) *ast.Ident {
+	for {
+		if paren, _ := expr.(*ast.ParenExpr); paren != nil {
+			expr = paren.X
+		} else {
+			ident, _ := expr.(*ast.Ident)
+			return ident
+		}
+	}
+}
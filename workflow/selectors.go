@@ -0,0 +1,287 @@
+package workflow
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// ConsumedChannels is the object fact of whether each receive channel param of
+// a function, by index, is received from, directly or by passing it to
+// another function that receives from it.
+type ConsumedChannels map[int]bool
+
+// AFact is for implementing golang.org/x/tools/go/analysis.Fact.
+func (*ConsumedChannels) AFact() {}
+
+// String returns whether each param is consumed, sorted by index.
+func (c *ConsumedChannels) String() string {
+	if c == nil {
+		return "<none>"
+	}
+	strs := make([]string, 0, len(*c))
+	for index, consumed := range *c {
+		if consumed {
+			strs = append(strs, fmt.Sprintf("param %v consumed", index))
+		} else {
+			strs = append(strs, fmt.Sprintf("param %v not consumed", index))
+		}
+	}
+	sort.Strings(strs)
+	return strings.Join(strs, ", ")
+}
+
+// selectorAddReceiveFuncs are the qualified names of the methods that add a
+// channel to a selector with a callback given the channel.
+var selectorAddReceiveFuncs = map[string]bool{
+	"(go.temporal.io/sdk/workflow.Selector).AddReceive": true,
+	"(go.temporal.io/sdk/internal.Selector).AddReceive": true,
+}
+
+// channelReceiveMethods are the names of the methods that consume a value from
+// a receive channel.
+var channelReceiveMethods = map[string]bool{
+	"Receive":                  true,
+	"ReceiveAsync":             true,
+	"ReceiveWithTimeout":       true,
+	"ReceiveAsyncWithMoreFlag": true,
+}
+
+// SelectorConfig is config for NewSelectorChecker.
+type SelectorConfig struct {
+	// If nil, uses log.Printf.
+	DebugfFunc func(string, ...interface{})
+	// Must be set to true to see advanced debug logs.
+	Debug bool
+}
+
+// SelectorChecker checks that callbacks given to Selector.AddReceive receive
+// from the channel, since otherwise the selector fires again immediately.
+type SelectorChecker struct {
+	*checkerDebug
+}
+
+// NewSelectorChecker creates a SelectorChecker for the given config.
+func NewSelectorChecker(config SelectorConfig) *SelectorChecker {
+	// Build checker
+	return &SelectorChecker{
+		checkerDebug: newCheckerDebug(config.DebugfFunc, config.Debug),
+	}
+}
+
+// NewAnalyzer creates a Go analysis analyzer that can be used in existing
+// tools. There is a -selector-debug flag for enabling debug logs. This analyzer
+// does not have any results but does set *ConsumedChannels facts on functions
+// and *VarFuncs facts on package-level vars.
+func (c *SelectorChecker) NewAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "workflowselectors",
+		Doc:       "Analyzes selector receive callbacks for never receiving from the channel",
+		Run:       func(p *analysis.Pass) (interface{}, error) { return nil, c.Run(p) },
+		FactTypes: []analysis.Fact{&ConsumedChannels{}, &VarFuncs{}},
+	}
+	// Set flags
+	a.Flags.BoolVar(&c.Debug, "selector-debug", c.Debug, "show selector debug output")
+	return a
+}
+
+// Run executes this checker for the given pass.
+func (c *SelectorChecker) Run(pass *analysis.Pass) error {
+	return c.run(newCheckedPackage(pass, false))
+}
+
+func (c *SelectorChecker) run(p *checkedPackage) error {
+	pass := p.pass
+	if !callGraphPackage(pass.Pkg) {
+		return nil
+	}
+	c.debugf("Checking selectors of package %v", pass.Pkg.Path())
+	f := &channelConsumerFinder{
+		SelectorChecker: c,
+		pass:            pass,
+		funcDecls:       p.resolver.funcDecls,
+		results:         map[*types.Func]ConsumedChannels{},
+		walking:         map[*types.Func]bool{},
+	}
+	// Set facts for every function with receive channel params
+	for fn := range f.funcDecls {
+		f.funcConsumes(fn)
+	}
+	// Check every receive callback
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			callExpr, _ := n.(*ast.CallExpr)
+			if callExpr == nil || len(callExpr.Args) != 2 {
+				return true
+			}
+			callee, _ := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+			if callee == nil || !selectorAddReceiveFuncs[callee.FullName()] {
+				return true
+			}
+			if name, consumes := f.callbackConsumes(callExpr.Args[0], callExpr.Args[1]); !consumes {
+				pass.Reportf(callExpr.Pos(), "AddReceive callback %v never receives from channel %v",
+					name, types.ExprString(callExpr.Args[0]))
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+type channelConsumerFinder struct {
+	*SelectorChecker
+	pass      *analysis.Pass
+	funcDecls map[*types.Func]*ast.FuncDecl
+	// Consumed channels of functions in this package already found
+	results map[*types.Func]ConsumedChannels
+	// Functions currently being walked to prevent recursion
+	walking map[*types.Func]bool
+}
+
+// callbackConsumes returns the name of the callback and whether it may
+// consume the channel. Callbacks that cannot be resolved are assumed to.
+func (f *channelConsumerFinder) callbackConsumes(ch ast.Expr, callback ast.Expr) (string, bool) {
+	// The callback may also receive from the channel var given to AddReceive
+	var chVar *types.Var
+	if ident := calleeIdent(ch); ident != nil {
+		chVar, _ = f.pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	}
+	if lit, _ := callback.(*ast.FuncLit); lit != nil {
+		if chVar != nil && f.varConsumed(lit.Body, chVar) {
+			return "func literal", true
+		}
+		params := paramVars(f.pass, lit.Type)
+		return "func literal", len(params) > 0 && params[0] != nil && f.varConsumed(lit.Body, params[0])
+	}
+	var fn *types.Func
+	switch callback := callback.(type) {
+	case *ast.Ident:
+		fn, _ = f.pass.TypesInfo.ObjectOf(callback).(*types.Func)
+	case *ast.SelectorExpr:
+		fn, _ = f.pass.TypesInfo.ObjectOf(callback.Sel).(*types.Func)
+	}
+	if fn == nil {
+		return types.ExprString(callback), true
+	}
+	funcDecl := f.funcDecls[fn]
+	if funcDecl != nil && funcDecl.Body != nil && chVar != nil && f.varConsumed(funcDecl.Body, chVar) {
+		return fn.FullName(), true
+	}
+	consumed, known := f.paramConsumed(fn, 0)
+	return fn.FullName(), consumed || !known
+}
+
+// paramConsumed returns whether the param at the given index of the function
+// is consumed and whether that is known.
+func (f *channelConsumerFinder) paramConsumed(fn *types.Func, index int) (consumed bool, known bool) {
+	var consumes ConsumedChannels
+	if fn.Pkg() == f.pass.Pkg {
+		consumes = f.funcConsumes(fn)
+	} else {
+		f.pass.ImportObjectFact(fn, &consumes)
+	}
+	consumed, known = consumes[index]
+	return
+}
+
+// funcConsumes returns whether each receive channel param of the given
+// function in this package is consumed, setting the fact if there are any.
+func (f *channelConsumerFinder) funcConsumes(fn *types.Func) ConsumedChannels {
+	if consumes, ok := f.results[fn]; ok {
+		return consumes
+	} else if f.walking[fn] || f.funcDecls[fn] == nil || f.funcDecls[fn].Body == nil {
+		return nil
+	}
+	f.walking[fn] = true
+	defer delete(f.walking, fn)
+	funcDecl := f.funcDecls[fn]
+	consumes := ConsumedChannels{}
+	for index, v := range paramVars(f.pass, funcDecl.Type) {
+		if v != nil && isReceiveChannel(v.Type()) {
+			consumes[index] = f.varConsumed(funcDecl.Body, v)
+		}
+	}
+	f.results[fn] = consumes
+	if len(consumes) > 0 {
+		f.pass.ExportObjectFact(fn, &consumes)
+	}
+	return consumes
+}
+
+// paramVars returns the var of each param of the function type by index, with
+// nil for unnamed params.
+func paramVars(pass *analysis.Pass, funcType *ast.FuncType) (vars []*types.Var) {
+	for _, field := range funcType.Params.List {
+		if len(field.Names) == 0 {
+			vars = append(vars, nil)
+		}
+		for _, name := range field.Names {
+			v, _ := pass.TypesInfo.Defs[name].(*types.Var)
+			vars = append(vars, v)
+		}
+	}
+	return
+}
+
+// varConsumed returns true if the channel var may be received from in the
+// given node. Any use of the var other than calling a method on it or passing
+// it to a function known not to consume it is assumed to consume it.
+func (f *channelConsumerFinder) varConsumed(node ast.Node, v *types.Var) bool {
+	isVar := func(expr ast.Expr) bool {
+		ident := calleeIdent(expr)
+		return ident != nil && f.pass.TypesInfo.Uses[ident] == v
+	}
+	uses, nonConsumingUses := 0, 0
+	consumed := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if consumed {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.Ident:
+			if f.pass.TypesInfo.Uses[n] == v {
+				uses++
+			}
+		case *ast.CallExpr:
+			// Methods on the channel
+			if sel, _ := n.Fun.(*ast.SelectorExpr); sel != nil && isVar(sel.X) {
+				if channelReceiveMethods[sel.Sel.Name] {
+					consumed = true
+				} else {
+					nonConsumingUses++
+				}
+			}
+			// Channel given to functions
+			fn := typeutil.StaticCallee(f.pass.TypesInfo, n)
+			if fn == nil || n.Ellipsis.IsValid() {
+				return true
+			}
+			for index, arg := range n.Args {
+				if isVar(arg) {
+					if paramConsumed, known := f.paramConsumed(fn, index); known && !paramConsumed {
+						nonConsumingUses++
+					}
+				}
+			}
+		}
+		return true
+	})
+	return consumed || uses > nonConsumingUses
+}
+
+// isReceiveChannel returns true if the type is a workflow channel or receive
+// channel, which may be aliases of the internal channels.
+func isReceiveChannel(t types.Type) bool {
+	named, _ := t.(*types.Named)
+	if named == nil || named.Obj().Pkg() == nil ||
+		(named.Obj().Name() != "ReceiveChannel" && named.Obj().Name() != "Channel") {
+		return false
+	}
+	path := named.Obj().Pkg().Path()
+	return path == "go.temporal.io/sdk/workflow" || path == "go.temporal.io/sdk/internal"
+}
@@ -0,0 +1,18 @@
+package workflow
+
+import "go/types"
+
+// signatureProblems returns the reasons the SDK would reject the given
+// signature as a workflow function at registration time.
+func signatureProblems(sig *types.Signature) (problems []string) {
+	if sig.Params().Len() == 0 || !isWorkflowContext(sig.Params().At(0).Type()) {
+		problems = append(problems, "first parameter must be workflow.Context")
+	}
+	switch results := sig.Results(); {
+	case results.Len() == 0 || results.Len() > 2:
+		problems = append(problems, "must return either an error or a result and an error")
+	case !isError(results.At(results.Len() - 1).Type()):
+		problems = append(problems, "last result must be an error")
+	}
+	return
+}
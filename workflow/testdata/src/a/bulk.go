@@ -0,0 +1,46 @@
+package a
+
+import (
+	"go.temporal.io/sdk/worker"
+)
+
+var Workflows = []interface{}{WorkflowNop, WorkflowCallTime} // want Workflows:"a.WorkflowNop, a.WorkflowCallTime"
+
+var WorkflowsByName = map[string]interface{}{"nop": WorkflowNop, "iterate": WorkflowIterateMap} // want WorkflowsByName:"a.WorkflowNop, a.WorkflowIterateMap"
+
+func PrepBulkWorkflows() { // want PrepBulkWorkflows:"iterates over map"
+	var wrk worker.Worker
+	for _, wf := range []interface{}{WorkflowNop, WorkflowCallTime} {
+		wrk.RegisterWorkflow(wf) // want "a.WorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+	}
+	for _, wf := range Workflows {
+		wrk.RegisterWorkflow(wf) // want "a.WorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+	}
+	for _, wf := range WorkflowsByName {
+		wrk.RegisterWorkflow(wf) // want "a.WorkflowIterateMap is non-deterministic, reason: iterates over map"
+	}
+	wrk.RegisterWorkflow(Workflows[1]) // want "a.WorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+	wrk.RegisterWorkflow(Workflows[0])
+	wrk.RegisterWorkflow(WorkflowsByName["nop"])
+	wrk.RegisterWorkflow(WorkflowsByName["iterate"]) // want "a.WorkflowIterateMap is non-deterministic, reason: iterates over map"
+	wrk.RegisterWorkflow([...]interface{}{2: WorkflowNop, 1: WorkflowCallTime}[2])
+	wf := WorkflowCallTimeTransitively
+	wrk.RegisterWorkflow(wf) // want "a.WorkflowCallTimeTransitively is non-deterministic, reason: calls non-determistic function a.SomeTimeCall"
+	var wfs []interface{}
+	wfs = append(wfs, WorkflowNop)
+	wfs = append(wfs, WorkflowIterateMap)
+	for _, wf := range wfs {
+		wrk.RegisterWorkflow(wf) // want "a.WorkflowIterateMap is non-deterministic, reason: iterates over map"
+	}
+	for _, wf := range unknownWorkflows() {
+		wrk.RegisterWorkflow(wf) // want "unrecognized function reference format"
+	}
+}
+
+func PrepWorkflowsFromParam(wrk worker.Worker, wf interface{}) {
+	wrk.RegisterWorkflow(wf) // want "unrecognized function reference format"
+}
+
+func unknownWorkflows() []interface{} {
+	return nil
+}
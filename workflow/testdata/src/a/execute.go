@@ -0,0 +1,57 @@
+package a
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func PrepParentWorkflow() {
+	var wrk worker.Worker
+	wrk.RegisterWorkflow(ParentWorkflow)
+}
+
+func ParentWorkflow(ctx workflow.Context) error {
+	workflow.ExecuteChildWorkflow(ctx, ChildWorkflowCallTime) // want "a.ChildWorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+	workflow.ExecuteChildWorkflow(ctx, ChildWorkflowCallTime)
+	workflow.ExecuteChildWorkflow(ctx, WorkflowNop)
+	// Registered elsewhere so only reported at the registration
+	workflow.ExecuteChildWorkflow(ctx, WorkflowCallTime)
+	// Executions by name are not checked
+	workflow.ExecuteChildWorkflow(ctx, "SomeWorkflow")
+	return nil
+}
+
+func ChildWorkflowCallTime(ctx workflow.Context) error { // want ChildWorkflowCallTime:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
+
+func StartWorkflows(c client.Client) {
+	c.ExecuteWorkflow(context.Background(), client.StartWorkflowOptions{}, ClientWorkflowCallTime)                              // want "a.ClientWorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+	c.SignalWithStartWorkflow(context.Background(), "id", "signal", nil, client.StartWorkflowOptions{}, SignalWorkflowCallTime) // want "a.SignalWorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+	c.ExecuteWorkflow(context.Background(), client.StartWorkflowOptions{}, "SomeWorkflow")
+}
+
+func ClientWorkflowCallTime(ctx workflow.Context) error { // want ClientWorkflowCallTime:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
+
+func SignalWorkflowCallTime(ctx workflow.Context) error { // want SignalWorkflowCallTime:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
+
+func TestWorkflowEnvironment(env *testsuite.TestWorkflowEnvironment) {
+	env.ExecuteWorkflow(TestedWorkflowCallTime) // want "a.TestedWorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+}
+
+func TestedWorkflowCallTime(ctx workflow.Context) error { // want TestedWorkflowCallTime:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
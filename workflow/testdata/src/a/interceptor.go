@@ -0,0 +1,47 @@
+package a
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+)
+
+type tracingInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+func (t *tracingInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	return t.Next.Init(&tracingOutbound{WorkflowOutboundInterceptorBase: interceptor.WorkflowOutboundInterceptorBase{Next: outbound}})
+}
+
+func (t *tracingInbound) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (interface{}, error) { // want ExecuteWorkflow:"calls non-determistic function time.Now" "\\(\\*a.tracingInbound\\).ExecuteWorkflow is non-deterministic, reason: calls non-determistic function time.Now" "\\(\\*a.tracingInbound\\).ExecuteWorkflow is non-deterministic, reason: calls non-determistic function \\(\\*a.tracingInbound\\).record"
+	start := time.Now()
+	defer t.record(start)
+	return t.Next.ExecuteWorkflow(ctx, in)
+}
+
+// Not part of the interface so only checked through its callers
+func (t *tracingInbound) record(start time.Time) { // want record:"calls non-determistic function time.Since"
+	time.Since(start)
+}
+
+type tracingOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+}
+
+func (t *tracingOutbound) ExecuteActivity(ctx workflow.Context, activityType string, args ...interface{}) workflow.Future {
+	return t.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+func (t tracingOutbound) Sleep(ctx workflow.Context, d time.Duration) error { // want Sleep:"calls non-determistic function time.Sleep" "\\(a.tracingOutbound\\).Sleep is non-deterministic, reason: calls non-determistic function time.Sleep"
+	time.Sleep(d)
+	return nil
+}
+
+// Does not embed the base so does not implement the interceptor
+type notInterceptor struct{}
+
+func (notInterceptor) Now(ctx workflow.Context) time.Time { // want Now:"calls non-determistic function time.Now"
+	return time.Now()
+}
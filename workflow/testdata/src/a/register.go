@@ -0,0 +1,36 @@
+package a
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+const orderWorkflowName = "order"
+
+var variableWorkflowName = "variable"
+
+func PrepNamedWorkflows() { // want PrepNamedWorkflows:"calls non-determistic function time.Now"
+	var wrk worker.Worker
+	wrk.RegisterWorkflowWithOptions(NamedWorkflowCallTime, workflow.RegisterOptions{Name: "payment"})         // want "payment \\(a.NamedWorkflowCallTime\\) is non-deterministic, reason: calls non-determistic function time.Now"
+	wrk.RegisterWorkflowWithOptions(NamedWorkflowCallTime, workflow.RegisterOptions{Name: orderWorkflowName}) // want "order \\(a.NamedWorkflowCallTime\\) is non-deterministic"
+	opts := workflow.RegisterOptions{Name: "shipping"}
+	wrk.RegisterWorkflowWithOptions(func(ctx workflow.Context) error { // want "shipping \\(func literal\\) is non-deterministic"
+		time.Now()
+		return nil
+	}, opts)
+	// Non-constant names use only the function name
+	wrk.RegisterWorkflowWithOptions(NamedWorkflowCallTime, workflow.RegisterOptions{Name: variableWorkflowName}) // want "^a.NamedWorkflowCallTime is non-deterministic"
+	wrk.RegisterDynamicWorkflow(DynamicWorkflowCallTime, workflow.DynamicRegisterOptions{})                      // want "dynamic workflow \\(a.DynamicWorkflowCallTime\\) is non-deterministic, reason: calls non-determistic function time.Now"
+}
+
+func NamedWorkflowCallTime(ctx workflow.Context) error { // want NamedWorkflowCallTime:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
+
+func DynamicWorkflowCallTime(ctx workflow.Context, args interface{}) error { // want DynamicWorkflowCallTime:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
@@ -0,0 +1,40 @@
+package a
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func PrepInvalidSignatureWorkflows() {
+	var wrk worker.Worker
+	wrk.RegisterWorkflow(WorkflowNoContext)      // want "a.WorkflowNoContext has invalid workflow signature: first parameter must be workflow.Context"
+	wrk.RegisterWorkflow(WorkflowStdContext)     // want "a.WorkflowStdContext has invalid workflow signature: first parameter must be workflow.Context"
+	wrk.RegisterWorkflow(WorkflowNoError)        // want "a.WorkflowNoError has invalid workflow signature: last result must be an error"
+	wrk.RegisterWorkflow(WorkflowTooManyResults) // want "a.WorkflowTooManyResults has invalid workflow signature: must return either an error or a result and an error"
+	wrk.RegisterWorkflow(WorkflowWithResult)
+	wrk.RegisterWorkflow(func() {}) // want "func literal has invalid workflow signature: first parameter must be workflow.Context" "func literal has invalid workflow signature: must return either an error or a result and an error"
+}
+
+func WorkflowNoContext(name string) error { return nil }
+
+func WorkflowStdContext(ctx context.Context) error { return nil }
+
+func WorkflowNoError(ctx workflow.Context) string { return "" }
+
+func WorkflowTooManyResults(ctx workflow.Context) (string, string, error) { return "", "", nil }
+
+func WorkflowWithResult(ctx workflow.Context, name string) (string, error) { return name, nil }
+
+func ParentOfInvalidChild(ctx workflow.Context) error {
+	workflow.ExecuteChildWorkflow(ctx, ChildWorkflowNoError) // want "a.ChildWorkflowNoError has invalid workflow signature: must return either an error or a result and an error"
+	return nil
+}
+
+func ChildWorkflowNoError(ctx workflow.Context) {}
+
+//temporal:workflow
+func DirectiveWorkflowNoContext() error { // want "a.DirectiveWorkflowNoContext has invalid workflow signature: first parameter must be workflow.Context"
+	return nil
+}
@@ -1,6 +1,7 @@
 package a
 
 import (
+	"fmt"
 	"time"
 
 	"go.temporal.io/sdk/worker"
@@ -10,9 +11,11 @@ import (
 func PrepWorkflow() {
 	var wrk worker.Worker
 	wrk.RegisterWorkflow(WorkflowNop)
-	wrk.RegisterWorkflow(WorkflowCallTime)             // want "a.WorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+	wrk.RegisterWorkflow(WorkflowCallTime)             // want "a.WorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now \\(confidence: high\\)"
 	wrk.RegisterWorkflow(WorkflowCallTimeTransitively) // want "a.WorkflowCallTimeTransitively is non-deterministic, reason: calls non-determistic function a.SomeTimeCall"
-	wrk.RegisterWorkflow(WorkflowIterateMap)           // want "a.WorkflowIterateMap is non-deterministic, reason: iterates over map"
+	wrk.RegisterWorkflow(WorkflowIterateMap)           // want "a.WorkflowIterateMap is non-deterministic, reason: iterates over map \\(confidence: medium\\)"
+	wrk.RegisterWorkflow(WorkflowPrint)                // want "a.WorkflowPrint is non-deterministic, reason: calls non-determistic function fmt.Println \\(confidence: medium\\)"
+	wrk.RegisterWorkflow(WorkflowIterateMapDeep)       // want "a.WorkflowIterateMapDeep is non-deterministic, reason: calls non-determistic function a.IterateMapDeep1 \\(confidence: low\\)"
 }
 
 func WorkflowNop(ctx workflow.Context) error {
@@ -39,3 +42,77 @@ func WorkflowIterateMap(ctx workflow.Context) error { // want WorkflowIterateMap
 	}
 	return nil
 }
+
+func WorkflowPrint(ctx workflow.Context) error { // want WorkflowPrint:"calls non-determistic function fmt.Println"
+	fmt.Println("Hello")
+	return nil
+}
+
+func WorkflowIterateMapDeep(ctx workflow.Context) error { // want WorkflowIterateMapDeep:"calls non-determistic function a.IterateMapDeep1"
+	IterateMapDeep1()
+	return nil
+}
+
+func IterateMapDeep1() { IterateMapDeep2() } // want IterateMapDeep1:"calls non-determistic function a.IterateMapDeep2"
+
+func IterateMapDeep2() { IterateMapDeep3() } // want IterateMapDeep2:"calls non-determistic function a.IterateMapDeep3"
+
+func IterateMapDeep3() { IterateMapDeep4() } // want IterateMapDeep3:"calls non-determistic function a.IterateMapDeep4"
+
+func IterateMapDeep4() { // want IterateMapDeep4:"iterates over map"
+	var m map[string]string
+	for range m {
+	}
+}
+
+func PrepLiteralWorkflows() { // want PrepLiteralWorkflows:"calls non-determistic function time.Now"
+	var wrk worker.Worker
+	wrk.RegisterWorkflow(func(ctx workflow.Context) error { // want "func literal is non-deterministic, reason: calls non-determistic function time.Now"
+		time.Now()
+		return nil
+	})
+	wrk.RegisterWorkflowWithOptions(func(ctx workflow.Context) error { return nil }, workflow.RegisterOptions{})
+	wrk.RegisterWorkflow(newWorkflowCallTime(time.Second)) // want "func literal returned from a.newWorkflowCallTime is non-deterministic, reason: calls non-determistic function a.SomeTimeCall"
+	wrk.RegisterWorkflow(newWorkflowNop())
+	wrk.RegisterWorkflow(newNamedWorkflow(true)) // want "a.WorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now"
+	wrk.RegisterWorkflow(newUnknownWorkflow())   // want "unrecognized function reference format"
+}
+
+func newWorkflowCallTime(timeout time.Duration) func(workflow.Context) error { // want newWorkflowCallTime:"calls non-determistic function a.SomeTimeCall"
+	return func(ctx workflow.Context) error {
+		if SomeTimeCall().IsZero() {
+			return nil
+		}
+		return nil
+	}
+}
+
+func newWorkflowNop() func(workflow.Context) error {
+	return func(ctx workflow.Context) error {
+		return nil
+	}
+}
+
+func newNamedWorkflow(callTime bool) func(workflow.Context) error {
+	if callTime {
+		return WorkflowCallTime
+	}
+	return WorkflowNop
+}
+
+var unknownWorkflow func(workflow.Context) error
+
+func newUnknownWorkflow() func(workflow.Context) error {
+	return unknownWorkflow
+}
+
+//temporal:workflow
+func DirectiveWorkflow(ctx workflow.Context) error { // want DirectiveWorkflow:"calls non-determistic function time.Now" "a.DirectiveWorkflow is non-deterministic, reason: calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
+
+//temporal:workflow
+func DirectiveWorkflowNop(ctx workflow.Context) error {
+	return nil
+}
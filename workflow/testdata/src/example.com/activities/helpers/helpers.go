@@ -0,0 +1,18 @@
+package helpers
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// No fact since functions taking a workflow context are workflow code
+func Log(ctx workflow.Context, msg string) {
+	workflow.GetLogger(ctx).Info(msg)
+}
+
+func Pure(a, b int) int { return a + b }
+
+func Now() time.Time { // want Now:"calls workflow API go.temporal.io/sdk/workflow.Now"
+	return workflow.Now(nil)
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"example.com/activities/helpers"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) { // want Register:"calls workflow API go.temporal.io/sdk/workflow.Now"
+	w.RegisterActivity(SleepActivity) // want `activity example.com/activities/worker.SleepActivity uses workflow-only APIs, reason: calls workflow API go.temporal.io/sdk/workflow.Sleep`
+	w.RegisterActivity(LogActivity)   // want `(?s)activity example.com/activities/worker.LogActivity uses workflow-only APIs, reason: passes workflow.Context to example.com/activities/worker.log`
+	w.RegisterActivity(NowActivity)   // want `(?s)activity example.com/activities/worker.NowActivity uses workflow-only APIs, reason: calls function example.com/activities/worker.now\n  example.com/activities/worker.now uses workflow-only APIs, reason: calls function example.com/activities/helpers.Now\n    example.com/activities/helpers.Now uses workflow-only APIs, reason: calls workflow API go.temporal.io/sdk/workflow.Now`
+	w.RegisterActivity(ValidActivity)
+	w.RegisterActivity(ContextActivity)                  // want `activity example.com/activities/worker.ContextActivity uses workflow-only APIs, reason: has workflow.Context parameter ctx`
+	w.RegisterActivity(PassContextActivity)              // want `activity example.com/activities/worker.PassContextActivity uses workflow-only APIs, reason: passes workflow.Context to example.com/activities/worker.keep`
+	w.RegisterActivity(&Activities{})                    // want `activity \(\*example.com/activities/worker.Activities\).Now uses workflow-only APIs, reason: calls workflow API go.temporal.io/sdk/workflow.Now`
+	w.RegisterActivity(func(ctx context.Context) error { // want `activity func literal uses workflow-only APIs, reason: calls workflow API go.temporal.io/sdk/workflow.Now`
+		workflow.Now(nil)
+		return nil
+	})
+	// Workflows may use workflow APIs
+	w.RegisterWorkflow(SleepWorkflow)
+}
+
+func SleepActivity(ctx context.Context) error { // want SleepActivity:"calls workflow API go.temporal.io/sdk/workflow.Sleep"
+	return workflow.Sleep(nil, time.Second)
+}
+
+func LogActivity(ctx context.Context) error { // want LogActivity:"passes workflow.Context to example.com/activities/worker.log"
+	log(nil)
+	return nil
+}
+
+func log(ctx workflow.Context) {
+	helpers.Log(ctx, "hello")
+}
+
+func NowActivity(ctx context.Context) (time.Time, error) { // want NowActivity:"calls function example.com/activities/worker.now"
+	return now(), nil
+}
+
+func now() time.Time { // want now:"calls function example.com/activities/helpers.Now"
+	return helpers.Now()
+}
+
+func ValidActivity(ctx context.Context) (int, error) {
+	return helpers.Pure(1, 2), nil
+}
+
+type Activities struct{}
+
+func (*Activities) Now(ctx context.Context) (time.Time, error) { // want Now:"calls workflow API go.temporal.io/sdk/workflow.Now"
+	return workflow.Now(nil), nil
+}
+
+func (*Activities) Valid(ctx context.Context) error { return nil }
+
+func SleepWorkflow(ctx workflow.Context) error {
+	return workflow.Sleep(ctx, time.Second)
+}
+
+func ContextActivity(ctx workflow.Context) error { return nil }
+
+func PassContextActivity(ctx context.Context) error { // want PassContextActivity:"passes workflow.Context to example.com/activities/worker.keep"
+	var wctx workflow.Context
+	keep(wctx)
+	return nil
+}
+
+func keep(ctx workflow.Context) {}
@@ -0,0 +1,31 @@
+package activities // want package:"\\(\\*example.com/activitycalls/activities.Activities\\).Ship, example.com/activitycalls/activities.SendEmail"
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/worker"
+)
+
+func ChargeCard(ctx context.Context, amount int) error { return nil }
+
+func SendEmail(ctx context.Context, to string) error { return nil }
+
+type Activities struct{}
+
+func (*Activities) Ship(ctx context.Context) error { return nil }
+
+// Not registered
+func Helper() int { return 1 }
+
+// Registered in bulk by the worker
+func Refund(ctx context.Context, amount int) error { return nil }
+
+type Billing struct{}
+
+func (*Billing) Invoice(ctx context.Context) error { return nil }
+
+// Registers activities on a worker from another package
+func Register(w worker.Worker) {
+	w.RegisterActivity(SendEmail)
+	w.RegisterActivity(&Activities{})
+}
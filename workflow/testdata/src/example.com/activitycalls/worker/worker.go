@@ -0,0 +1,32 @@
+package worker // want package:"\\(\\*example.com/activitycalls/activities.Activities\\).Ship, \\(\\*example.com/activitycalls/activities.Billing\\).Invoice, example.com/activitycalls/activities.ChargeCard, example.com/activitycalls/activities.Refund, example.com/activitycalls/activities.SendEmail"
+
+import (
+	"context"
+
+	"example.com/activitycalls/activities"
+	"example.com/activitycalls/workflows"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) { // want Register:"example.com/activitycalls/activities.SendEmail"
+	w.RegisterActivity(activities.ChargeCard)
+	activities.Register(w)
+	for _, a := range []interface{}{activities.Refund, &activities.Billing{}} {
+		w.RegisterActivity(a)
+	}
+	w.RegisterWorkflow(workflows.ChargeWorkflow)  // want `example.com/activitycalls/workflows.ChargeWorkflow is non-deterministic, reason: calls activity function example.com/activitycalls/activities.ChargeCard directly \(confidence: high\)`
+	w.RegisterWorkflow(workflows.NotifyWorkflow)  // want `(?s)example.com/activitycalls/workflows.NotifyWorkflow is non-deterministic, reason: calls non-determistic function example.com/activitycalls/workflows.notify \(confidence: high\).*notify is non-deterministic, reason: calls activity function example.com/activitycalls/activities.SendEmail directly`
+	w.RegisterWorkflow(workflows.ShipWorkflow)    // want `calls activity function \(\*example.com/activitycalls/activities.Activities\).Ship directly`
+	w.RegisterWorkflow(workflows.RefundWorkflow)  // want `calls activity function example.com/activitycalls/activities.Refund directly`
+	w.RegisterWorkflow(workflows.InvoiceWorkflow) // want `calls activity function \(\*example.com/activitycalls/activities.Billing\).Invoice directly`
+	w.RegisterWorkflow(workflows.ValidWorkflow)
+	w.RegisterWorkflow(func(ctx workflow.Context) error { // want `func literal is non-deterministic, reason: calls activity function example.com/activitycalls/activities.SendEmail directly`
+		return activities.SendEmail(context.Background(), "a@example.com")
+	})
+	w.RegisterWorkflow(chargeWorkflow) // want `example.com/activitycalls/worker.chargeWorkflow is non-deterministic, reason: calls activity function example.com/activitycalls/activities.ChargeCard directly`
+}
+
+func chargeWorkflow(ctx workflow.Context) error {
+	return activities.ChargeCard(context.Background(), 100)
+}
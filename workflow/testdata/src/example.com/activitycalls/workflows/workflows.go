@@ -0,0 +1,40 @@
+package workflows
+
+import (
+	"context"
+
+	"example.com/activitycalls/activities"
+	"go.temporal.io/sdk/workflow"
+)
+
+func ChargeWorkflow(ctx workflow.Context) error {
+	activities.Helper()
+	return activities.ChargeCard(context.Background(), 100)
+}
+
+func NotifyWorkflow(ctx workflow.Context) error {
+	return notify()
+}
+
+func notify() error {
+	return activities.SendEmail(context.Background(), "a@example.com")
+}
+
+func ShipWorkflow(ctx workflow.Context) error {
+	var a *activities.Activities
+	return a.Ship(context.Background())
+}
+
+func RefundWorkflow(ctx workflow.Context) error {
+	return activities.Refund(context.Background(), 100)
+}
+
+func InvoiceWorkflow(ctx workflow.Context) error {
+	var b activities.Billing
+	return b.Invoice(context.Background())
+}
+
+func ValidWorkflow(ctx workflow.Context) error {
+	activities.Helper()
+	return workflow.ExecuteActivity(ctx, activities.ChargeCard, 100).Get(ctx, nil)
+}
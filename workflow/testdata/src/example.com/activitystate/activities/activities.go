@@ -0,0 +1,122 @@
+package activities
+
+import (
+	"context"
+	"sync"
+)
+
+type Stats struct{ Count int }
+
+type Activities struct {
+	mu       sync.Mutex
+	count    int
+	cache    map[string]string
+	results  []string
+	stats    *Stats
+	embedded Stats
+	Client   interface{}
+}
+
+func (a *Activities) Increment(ctx context.Context) error { // want Increment:"writes count"
+	a.count++
+	return nil
+}
+
+func (a *Activities) Cache(ctx context.Context, k, v string) error { // want Cache:"writes cache\\[\\]"
+	a.cache[k] = v
+	delete(a.cache, "old")
+	return nil
+}
+
+func (a *Activities) Record(ctx context.Context, r string) error { // want Record:"writes results via \\(\\*example.com/activitystate/activities.Activities\\).appendResult"
+	a.appendResult(r)
+	return nil
+}
+
+func (a *Activities) appendResult(r string) { // want appendResult:"writes results"
+	a.results = append(a.results, r)
+}
+
+func (a *Activities) Locked(ctx context.Context, r string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.count++
+	a.appendResult(r)
+	return nil
+}
+
+func (a *Activities) Unlocked(ctx context.Context) error { // want Unlocked:"writes results"
+	a.mu.Lock()
+	a.count++
+	a.mu.Unlock()
+	a.results = nil
+	return nil
+}
+
+func (a *Activities) LockedInBlock(ctx context.Context, ok bool) error { // want LockedInBlock:"writes count"
+	if ok {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.count++
+	}
+	a.count++
+	return nil
+}
+
+var globalMu sync.Mutex
+
+func (a *Activities) OtherMutexes(ctx context.Context) error { // want OtherMutexes:"writes count"
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	var mu sync.Mutex
+	mu.Lock()
+	defer mu.Unlock()
+	func() { a.mu.Lock() }()
+	a.count++
+	return nil
+}
+
+func (a *Activities) Goroutine(ctx context.Context) error { // want Goroutine:"writes count"
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	go func() { a.count++ }()
+	return nil
+}
+
+func (a *Activities) Local(ctx context.Context) (int, error) {
+	count := a.count
+	count++
+	var local Activities
+	local.count++
+	return count, nil
+}
+
+func (a Activities) ValueReceiver(ctx context.Context) error { // want ValueReceiver:"writes stats.Count, writes cache\\[\\]"
+	// Copies are not shared but what they reference is
+	a.count++
+	a.embedded.Count++
+	a.stats.Count++
+	a.cache["k"] = "v"
+	return nil
+}
+
+type Embedded struct {
+	sync.Mutex
+	count int
+}
+
+func (e *Embedded) Increment(ctx context.Context) error {
+	e.Lock()
+	e.count++
+	e.Unlock()
+	return nil
+}
+
+type Counter struct{ n int }
+
+func (c *Counter) Add(ctx context.Context) error { // want Add:"writes n"
+	c.n++
+	return nil
+}
+
+func Ping(ctx context.Context) error { return nil }
@@ -0,0 +1,22 @@
+package worker
+
+import (
+	"example.com/activitystate/activities"
+	"go.temporal.io/sdk/worker"
+)
+
+func Register(w worker.Worker) {
+	w.RegisterActivity(&activities.Activities{}) // want `activity \(\*example.com/activitystate/activities.Activities\).Increment writes shared receiver state count without holding a mutex` `activity \(\*example.com/activitystate/activities.Activities\).Cache writes shared receiver state cache\[\] without holding a mutex` `activity \(\*example.com/activitystate/activities.Activities\).Record writes shared receiver state results without holding a mutex via \(\*example.com/activitystate/activities.Activities\).appendResult` `activity \(\*example.com/activitystate/activities.Activities\).Unlocked writes shared receiver state results without holding a mutex` `activity \(\*example.com/activitystate/activities.Activities\).LockedInBlock writes shared receiver state count without holding a mutex` `activity \(\*example.com/activitystate/activities.Activities\).OtherMutexes writes shared receiver state count without holding a mutex` `activity \(\*example.com/activitystate/activities.Activities\).Goroutine writes shared receiver state count without holding a mutex` `activity \(example.com/activitystate/activities.Activities\).ValueReceiver writes shared receiver state stats.Count without holding a mutex` `activity \(example.com/activitystate/activities.Activities\).ValueReceiver writes shared receiver state cache\[\] without holding a mutex`
+	w.RegisterActivity(&activities.Embedded{})
+	var a *activities.Activities
+	w.RegisterActivity(a.Increment) // want `activity \(\*example.com/activitystate/activities.Activities\).Increment writes shared receiver state count without holding a mutex`
+}
+
+func RegisterAll(w worker.Worker) {
+	// Registered through interface values
+	for _, a := range []interface{}{activities.Ping, &activities.Counter{}} {
+		w.RegisterActivity(a) // want `activity \(\*example.com/activitystate/activities.Counter\).Add writes shared receiver state n without holding a mutex`
+	}
+	var a interface{} = &activities.Counter{}
+	w.RegisterActivity(a) // want `activity \(\*example.com/activitystate/activities.Counter\).Add writes shared receiver state n without holding a mutex`
+}
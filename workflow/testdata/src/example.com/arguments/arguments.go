@@ -0,0 +1,75 @@
+package arguments
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+type Order struct{ ID string }
+
+func Charge(ctx context.Context, order *Order, amount int64) (string, error) { return "", nil }
+
+func Notify(ctx context.Context, emails ...string) error { return nil }
+
+func NoContext(name string) error { return nil }
+
+type Activities struct{}
+
+func (*Activities) Ship(ctx context.Context, order Order) (int, error) { return 0, nil }
+
+func Child(ctx workflow.Context, order Order) (bool, error) { return false, nil }
+
+func ParentWorkflow(ctx workflow.Context, order *Order) error {
+	// Valid
+	var id string
+	if err := workflow.ExecuteActivity(ctx, Charge, order, 100).Get(ctx, &id); err != nil {
+		return err
+	}
+	workflow.ExecuteActivity(ctx, Charge, nil, int64(100))
+	workflow.ExecuteActivity(ctx, Notify)
+	workflow.ExecuteActivity(ctx, Notify, "a", "b")
+	workflow.ExecuteActivity(ctx, NoContext, "name")
+	workflow.ExecuteLocalActivity(ctx, Charge, order, 100)
+	// Pointers and numbers of different sizes are the same in JSON
+	workflow.ExecuteActivity(ctx, Charge, *order, int32(100))
+	workflow.ExecuteActivity(ctx, Charge, &order, 100.0)
+	// Unknown or by name are not checked
+	workflow.ExecuteActivity(ctx, "Charge", 1, 2, 3)
+	args := []interface{}{order, 100}
+	workflow.ExecuteActivity(ctx, Charge, args...)
+
+	// Invalid count
+	workflow.ExecuteActivity(ctx, Charge, order)                // want `activity example.com/arguments.Charge expects 2 arguments but was given 1`
+	workflow.ExecuteActivity(ctx, NoContext)                    // want `activity example.com/arguments.NoContext expects 1 arguments but was given 0`
+	workflow.ExecuteChildWorkflow(ctx, Child, Order{}, Order{}) // want `child workflow example.com/arguments.Child expects 1 arguments but was given 2`
+	// Invalid types
+	workflow.ExecuteActivity(ctx, Charge, order, "100") // want `activity example.com/arguments.Charge argument 2 has type string but parameter has type int64`
+	workflow.ExecuteActivity(ctx, Charge, order, 1.5)   // want `activity example.com/arguments.Charge argument 2 has type float64 but parameter has type int64`
+	var amount float64
+	workflow.ExecuteActivity(ctx, Charge, order, amount) // want `activity example.com/arguments.Charge argument 2 has type float64 but parameter has type int64`
+	workflow.ExecuteActivity(ctx, Notify, "a", 1)        // want `activity example.com/arguments.Notify argument 2 has type int but parameter has type string`
+	workflow.ExecuteChildWorkflow(ctx, Child, nil)       // want `child workflow example.com/arguments.Child argument 1 has type untyped nil but parameter has type Order`
+	workflow.ExecuteChildWorkflow(ctx, Child, "order")   // want `child workflow example.com/arguments.Child argument 1 has type string but parameter has type Order`
+
+	// Struct method pattern
+	var a *Activities
+	var shipped int
+	workflow.ExecuteActivity(ctx, a.Ship, *order).Get(ctx, &shipped)
+	workflow.ExecuteActivity(ctx, a.Ship, order)
+
+	// Invalid results
+	fut := workflow.ExecuteActivity(ctx, a.Ship, *order)
+	var shippedStr string
+	fut.Get(ctx, &shippedStr) // want `Get destination for activity \(\*example.com/arguments.Activities\).Ship has type \*string but result has type int`
+	fut.Get(ctx, shipped)     // want `Get destination for activity \(\*example.com/arguments.Activities\).Ship has type int but must be a pointer to int`
+	fut.Get(ctx, nil)
+	var shipped64 int64
+	fut.Get(ctx, &shipped64)
+	var shippedPtr *int
+	fut.Get(ctx, &shippedPtr)
+	workflow.ExecuteActivity(ctx, Notify).Get(ctx, &id) // want `activity example.com/arguments.Notify has no result but Get was given \*string`
+	var ok bool
+	workflow.ExecuteChildWorkflow(ctx, Child, *order).Get(ctx, &ok)
+	return nil
+}
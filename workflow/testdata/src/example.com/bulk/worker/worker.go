@@ -0,0 +1,17 @@
+package worker
+
+import (
+	"example.com/bulk/workflows"
+	"go.temporal.io/sdk/worker"
+)
+
+func Register(w worker.Worker) {
+	for _, wf := range workflows.All {
+		w.RegisterWorkflow(wf) // want "example.com/bulk/workflows.TimeWorkflow is non-deterministic, reason: calls non-determistic function time.Now"
+	}
+	w.RegisterWorkflow(workflows.All[1]) // want "example.com/bulk/workflows.TimeWorkflow is non-deterministic, reason: calls non-determistic function time.Now"
+	w.RegisterWorkflow(workflows.Single) // want "example.com/bulk/workflows.TimeWorkflow is non-deterministic, reason: calls non-determistic function time.Now"
+	for _, wf := range workflows.Literals {
+		w.RegisterWorkflow(wf) // want "unrecognized function reference format"
+	}
+}
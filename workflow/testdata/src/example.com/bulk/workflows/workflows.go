@@ -0,0 +1,23 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+var All = []interface{}{OrderWorkflow, TimeWorkflow} // want All:"example.com/bulk/workflows.OrderWorkflow, example.com/bulk/workflows.TimeWorkflow"
+
+var Single = TimeWorkflow // want Single:"example.com/bulk/workflows.TimeWorkflow"
+
+// Function literals cannot be checked from other packages
+var Literals = []interface{}{func(ctx workflow.Context) error { return nil }}
+
+func OrderWorkflow(ctx workflow.Context) error {
+	return nil
+}
+
+func TimeWorkflow(ctx workflow.Context) error { // want TimeWorkflow:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
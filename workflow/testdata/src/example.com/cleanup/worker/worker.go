@@ -0,0 +1,22 @@
+package worker
+
+import (
+	"example.com/cleanup/workflows"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) {
+	w.RegisterWorkflow(workflows.DeferredOriginal) // want `example.com/cleanup/workflows.DeferredOriginal may skip cleanup when cancelled, reason: deferred call calls go.temporal.io/sdk/workflow.ExecuteActivity with a context not from workflow.NewDisconnectedContext at .*workflows.go:\d+:\d+`
+	w.RegisterWorkflow(workflows.DeferredDirect)   // want `DeferredDirect may skip cleanup when cancelled`
+	w.RegisterWorkflow(workflows.DeferredDisconnected)
+	w.RegisterWorkflow(workflows.CancellationBranch) // want `CancellationBranch may skip cleanup when cancelled, reason: cancellation branch calls`
+	w.RegisterWorkflow(workflows.ErrBranch)          // want `ErrBranch may skip cleanup when cancelled`
+	w.RegisterWorkflow(workflows.NegatedBranch)      // want `NegatedBranch may skip cleanup when cancelled, reason: cancellation branch calls`
+	w.RegisterWorkflow(workflows.Reassigned)         // want `Reassigned may skip cleanup when cancelled, reason: deferred call calls`
+	w.RegisterWorkflow(workflows.ReassignedDisconnected)
+	w.RegisterWorkflow(func(ctx workflow.Context) error { // want `func literal may skip cleanup when cancelled, reason: deferred call`
+		defer workflow.ExecuteActivity(ctx, workflows.Cleanup)
+		return nil
+	})
+}
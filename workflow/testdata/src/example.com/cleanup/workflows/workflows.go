@@ -0,0 +1,83 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+func DeferredOriginal(ctx workflow.Context) error { // want DeferredOriginal:`deferred call calls go.temporal.io/sdk/workflow.ExecuteActivity with a context not from workflow.NewDisconnectedContext`
+	defer func() {
+		_ = workflow.ExecuteActivity(ctx, Cleanup).Get(ctx, nil)
+	}()
+	return workflow.ExecuteActivity(ctx, Work).Get(ctx, nil)
+}
+
+func DeferredDirect(ctx workflow.Context) error { // want DeferredDirect:`deferred call calls go.temporal.io/sdk/workflow.ExecuteLocalActivity`
+	defer workflow.ExecuteLocalActivity(ctx, Cleanup)
+	return nil
+}
+
+func DeferredDisconnected(ctx workflow.Context) error {
+	defer func() {
+		newCtx, cancel := workflow.NewDisconnectedContext(ctx)
+		defer cancel()
+		newCtx = workflow.WithActivityOptions(newCtx, workflow.ActivityOptions{})
+		_ = workflow.ExecuteActivity(newCtx, Cleanup).Get(newCtx, nil)
+	}()
+	return workflow.ExecuteActivity(ctx, Work).Get(ctx, nil)
+}
+
+func CancellationBranch(ctx workflow.Context) error { // want CancellationBranch:`cancellation branch calls go.temporal.io/sdk/workflow.ExecuteActivity with a context not from workflow.NewDisconnectedContext`
+	err := workflow.ExecuteActivity(ctx, Work).Get(ctx, nil)
+	if temporal.IsCanceledError(err) {
+		return workflow.ExecuteActivity(ctx, Cleanup).Get(ctx, nil)
+	}
+	return err
+}
+
+func ErrBranch(ctx workflow.Context) error { // want ErrBranch:`cancellation branch calls go.temporal.io/sdk/workflow.ExecuteActivity`
+	if ctx.Err() != nil {
+		cleanupCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{})
+		return workflow.ExecuteActivity(cleanupCtx, Cleanup).Get(ctx, nil)
+	}
+	disconnected, _ := workflow.NewDisconnectedContext(ctx)
+	if ctx.Err() != nil {
+		// Disconnected contexts are safe in deferred calls too
+		defer workflow.ExecuteActivity(disconnected, Cleanup)
+		return workflow.ExecuteActivity(disconnected, Cleanup).Get(disconnected, nil)
+	}
+	return nil
+}
+
+func NegatedBranch(ctx workflow.Context) error { // want NegatedBranch:`^cancellation branch calls go.temporal.io/sdk/workflow.ExecuteActivity with a context not from workflow.NewDisconnectedContext$`
+	err := workflow.ExecuteActivity(ctx, Work).Get(ctx, nil)
+	// Not cancelled in these branches
+	if ctx.Err() == nil {
+		_ = workflow.ExecuteActivity(ctx, Work).Get(ctx, nil)
+	}
+	if !temporal.IsCanceledError(err) {
+		return workflow.ExecuteActivity(ctx, Work).Get(ctx, nil)
+	} else {
+		// But cancelled in the else branch
+		return workflow.ExecuteActivity(ctx, Cleanup).Get(ctx, nil)
+	}
+}
+
+func Reassigned(ctx workflow.Context) error { // want Reassigned:`deferred call calls go.temporal.io/sdk/workflow.ExecuteActivity`
+	cleanupCtx, _ := workflow.NewDisconnectedContext(ctx)
+	// Only the last assignment before the execution is used
+	cleanupCtx = ctx
+	defer workflow.ExecuteActivity(cleanupCtx, Cleanup)
+	return nil
+}
+
+func ReassignedDisconnected(ctx workflow.Context) error {
+	cleanupCtx := ctx
+	cleanupCtx, _ = workflow.NewDisconnectedContext(cleanupCtx)
+	defer workflow.ExecuteActivity(cleanupCtx, Cleanup)
+	return nil
+}
+
+func Work() error { return nil }
+
+func Cleanup() error { return nil }
@@ -0,0 +1,44 @@
+package confidence
+
+import (
+	"time"
+
+	"example.com/thirdparty"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func PrepWorkflow() {
+	var wrk worker.Worker
+	wrk.RegisterWorkflow(WorkflowCallTime) // want "example.com/confidence.WorkflowCallTime is non-deterministic, reason: calls non-determistic function time.Now \\(confidence: high\\)"
+	wrk.RegisterWorkflow(WorkflowIterateMap)
+	wrk.RegisterWorkflow(WorkflowCallLocal) // want "example.com/confidence.WorkflowCallLocal is non-deterministic, reason: calls non-determistic function example.com/confidence.now \\(confidence: high\\)"
+	// Medium confidence since the chain passes through third-party code
+	wrk.RegisterWorkflow(WorkflowCallThirdParty)
+}
+
+func WorkflowCallTime(ctx workflow.Context) error { // want WorkflowCallTime:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
+
+func WorkflowIterateMap(ctx workflow.Context) error { // want WorkflowIterateMap:"iterates over map"
+	var m map[string]string
+	for range m {
+	}
+	return nil
+}
+
+func WorkflowCallLocal(ctx workflow.Context) error { // want WorkflowCallLocal:"calls non-determistic function example.com/confidence.now"
+	now()
+	return nil
+}
+
+func now() time.Time { // want now:"calls non-determistic function time.Now"
+	return time.Now()
+}
+
+func WorkflowCallThirdParty(ctx workflow.Context) error { // want WorkflowCallThirdParty:"calls non-determistic function example.com/thirdparty.Now"
+	thirdparty.Now()
+	return nil
+}
@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"example.com/continueasnew/workflows"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) {
+	w.RegisterWorkflow(workflows.Drained)
+	w.RegisterWorkflow(workflows.NotDrained)           // want `example.com/continueasnew/workflows.NotDrained may lose signals, reason: continues as new without draining signal channel ch \(signal "add"\) at .*workflows.go:\d+:\d+`
+	w.RegisterWorkflow(workflows.ConditionallyDrained) // want `ConditionallyDrained may lose signals`
+	w.RegisterWorkflow(workflows.DrainedByHelper)
+	w.RegisterWorkflow(workflows.MultipleChannels)   // want `MultipleChannels may lose signals, reason: continues as new without draining signal channel second at`
+	w.RegisterWorkflow(workflows.SelectorNotDrained) // want `SelectorNotDrained may lose signals, reason: continues as new without draining signal channel ch \(signal "add"\)`
+	w.RegisterWorkflow(func(ctx workflow.Context) error {
+		ch := workflow.GetSignalChannel(ctx, "stop")
+		workflows.Drain("stop", ch)
+		return workflow.NewContinueAsNewError(ctx, "Literal")
+	})
+	w.RegisterWorkflow(func(ctx workflow.Context) error { // want `func literal may lose signals, reason: continues as new without draining signal channel ch \(signal "stop"\)`
+		ch := workflow.GetSignalChannel(ctx, "stop")
+		workflows.Inspect(ch)
+		return workflow.NewContinueAsNewError(ctx, "Literal")
+	})
+	w.RegisterWorkflow(func(ctx workflow.Context) error { // want `func literal may lose signals, reason: continues as new without draining signal channel ch \(signal "stop"\)`
+		ch := workflow.GetSignalChannel(ctx, "stop")
+		ch.Receive(ctx, nil)
+		return workflow.NewContinueAsNewError(ctx, "Literal")
+	})
+}
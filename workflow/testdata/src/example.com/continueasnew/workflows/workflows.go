@@ -0,0 +1,79 @@
+package workflows
+
+import "go.temporal.io/sdk/workflow"
+
+func Drained(ctx workflow.Context, count int) error {
+	ch := workflow.GetSignalChannel(ctx, "add")
+	for i := 0; i < 100; i++ {
+		ch.Receive(ctx, &count)
+	}
+	for ch.ReceiveAsync(&count) {
+	}
+	return workflow.NewContinueAsNewError(ctx, Drained, count)
+}
+
+func NotDrained(ctx workflow.Context, count int) error { // want NotDrained:`continues as new without draining signal channel ch \(signal "add"\)`
+	ch := workflow.GetSignalChannel(ctx, "add")
+	for i := 0; i < 100; i++ {
+		ch.Receive(ctx, &count)
+	}
+	return workflow.NewContinueAsNewError(ctx, NotDrained, count)
+}
+
+func ConditionallyDrained(ctx workflow.Context, drain bool) error { // want ConditionallyDrained:`continues as new without draining signal channel ch \(signal "add"\)`
+	ch := workflow.GetSignalChannel(ctx, "add")
+	if drain {
+		ch.ReceiveAsync(nil)
+	}
+	err := workflow.NewContinueAsNewError(ctx, ConditionallyDrained, drain)
+	return err
+}
+
+func DrainedByHelper(ctx workflow.Context, name string) error {
+	ch := workflow.GetSignalChannel(ctx, name)
+	if name == "" {
+		// Other returns do not continue as new
+		return nil
+	}
+	drain(ch)
+	return workflow.NewContinueAsNewError(ctx, DrainedByHelper, name)
+}
+
+func MultipleChannels(ctx workflow.Context, name string) error { // want MultipleChannels:`continues as new without draining signal channel second`
+	first := workflow.GetSignalChannel(ctx, "first")
+	second := workflow.GetSignalChannel(ctx, name)
+	second.Receive(ctx, nil)
+	for {
+		if !first.ReceiveAsync(nil) {
+			break
+		}
+	}
+	return workflow.NewContinueAsNewError(ctx, MultipleChannels, name)
+}
+
+// Receiving through a selector or passing the channel to functions that do not
+// drain it does not drain it
+func SelectorNotDrained(ctx workflow.Context) error { // want SelectorNotDrained:`continues as new without draining signal channel ch \(signal "add"\)`
+	ch := workflow.GetSignalChannel(ctx, "add")
+	selector := workflow.NewSelector(ctx)
+	selector.AddReceive(ch, func(c workflow.ReceiveChannel, more bool) {
+		c.Receive(ctx, nil)
+	})
+	selector.Select(ctx)
+	workflow.GetLogger(ctx).Info("continuing", "channel", ch)
+	Inspect(ch)
+	return workflow.NewContinueAsNewError(ctx, SelectorNotDrained)
+}
+
+func drain(ch workflow.ReceiveChannel) { // want drain:"param 0 drained"
+	for ch.ReceiveAsync(nil) {
+	}
+}
+
+func Drain(name string, ch workflow.ReceiveChannel) { // want Drain:"param 1 drained"
+	drain(ch)
+}
+
+func Inspect(ch workflow.ReceiveChannel) {
+	ch.Len()
+}
@@ -0,0 +1,136 @@
+package futures
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Only code reachable from registered workflows is checked
+func Register(w worker.Worker) {
+	w.RegisterWorkflow(Discarded)
+	w.RegisterWorkflow(UncheckedGet)
+	w.RegisterWorkflow(Selected)
+	w.RegisterWorkflow(UnreadErr)
+	w.RegisterWorkflow(NamedResult)
+	w.RegisterWorkflow(Helpers)
+}
+
+func Discarded(ctx workflow.Context) error {
+	workflow.ExecuteActivity(ctx, Activity)         // want "future returned by go.temporal.io/sdk/workflow.ExecuteActivity is discarded"
+	_ = workflow.ExecuteChildWorkflow(ctx, Child)   // want "future returned by go.temporal.io/sdk/workflow.ExecuteChildWorkflow is discarded"
+	go workflow.ExecuteLocalActivity(ctx, Activity) // want "future returned by go.temporal.io/sdk/workflow.ExecuteLocalActivity is discarded"
+	startActivity(ctx)                              // want "future returned by example.com/futures.startActivity is discarded"
+	// Futures used are not discarded
+	f := workflow.ExecuteActivity(ctx, Activity)
+	return f.Get(ctx, nil)
+}
+
+func UncheckedGet(ctx workflow.Context) error {
+	workflow.ExecuteActivity(ctx, Activity).Get(ctx, nil) // want `error from Get on future workflow.ExecuteActivity\(ctx, Activity\) is not checked`
+	f := workflow.ExecuteActivity(ctx, Activity)
+	_ = f.Get(ctx, nil)   // want "error from Get on future f is not checked"
+	defer f.Get(ctx, nil) // want "error from Get on future f is not checked"
+	if err := f.Get(ctx, nil); err != nil {
+		return err
+	}
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		f.Get(ctx, nil) // want "error from Get on future f is not checked"
+	})
+	return nil
+}
+
+func Selected(ctx workflow.Context) error {
+	timedOut := false
+	selector := workflow.NewSelector(ctx)
+	// Futures added to a selector may have their Get error ignored in the
+	// callback
+	selector.AddFuture(workflow.NewTimer(ctx, time.Minute), func(f workflow.Future) {
+		_ = f.Get(ctx, nil)
+		timedOut = true
+	})
+	// Including the var of the future added and with callbacks in vars
+	f := workflow.ExecuteActivity(ctx, Activity)
+	selector.AddFuture(f, func(workflow.Future) {
+		f.Get(ctx, nil)
+	})
+	onDone := func(f workflow.Future) {
+		f.Get(ctx, nil)
+	}
+	selector.AddFuture(workflow.ExecuteActivity(ctx, Activity), onDone)
+	for i := 0; i < 3; i++ {
+		selector.Select(ctx)
+	}
+	_ = timedOut
+	// Futures never added to a selector are still checked
+	other := workflow.ExecuteActivity(ctx, Activity)
+	onOther := func(workflow.Future) {
+		other.Get(ctx, nil) // want "error from Get on future other is not checked"
+	}
+	onOther(other)
+	return nil
+}
+
+func UnreadErr(ctx workflow.Context) error {
+	f := workflow.ExecuteActivity(ctx, Activity)
+	err := workflow.ExecuteActivity(ctx, Activity).Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = f.Get(ctx, nil) // want "error from Get on future f is not checked"
+	// Errors read after, in a later loop iteration, or as a named result are
+	// checked
+	err = f.Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 2; i++ {
+		if err != nil {
+			return err
+		}
+		err = f.Get(ctx, nil)
+	}
+	return nil
+}
+
+func NamedResult(ctx workflow.Context) (err error) {
+	err = workflow.ExecuteActivity(ctx, Activity).Get(ctx, nil)
+	return
+}
+
+func startActivity(ctx workflow.Context) workflow.Future {
+	return workflow.ExecuteActivity(ctx, Activity)
+}
+
+func Helpers(ctx workflow.Context) error {
+	f := workflow.ExecuteActivity(ctx, Activity)
+	getResult(f)
+	selector := workflow.NewSelector(ctx)
+	selector.AddFuture(f, onSelected)
+	selector.Select(ctx)
+	return nil
+}
+
+// Functions called from workflows are checked without a workflow context
+func getResult(f workflow.Future) {
+	f.Get(nil, nil) // want "error from Get on future f is not checked"
+}
+
+// Including named selector callbacks, whose future is selected
+func onSelected(f workflow.Future) {
+	f.Get(nil, nil)
+}
+
+// Functions not reachable from workflows are not checked
+func notWorkflow(f workflow.Future) {
+	f.Get(nil, nil)
+}
+
+func Unregistered(ctx workflow.Context) {
+	workflow.ExecuteActivity(ctx, Activity)
+}
+
+func Activity() error { return nil }
+
+func Child(ctx workflow.Context) error { return nil }
@@ -0,0 +1,15 @@
+package clean
+
+import (
+	"strings"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Forbidden imports of the SDK itself are not reported
+var _ converter.Payload
+
+func CleanWorkflow(ctx workflow.Context, name string) (string, error) {
+	return strings.ToUpper(name), nil
+}
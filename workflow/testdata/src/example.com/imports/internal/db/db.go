@@ -0,0 +1,7 @@
+package db // want package:"database/sql"
+
+import "database/sql"
+
+func Open() (*sql.DB, error) {
+	return sql.Open("postgres", "")
+}
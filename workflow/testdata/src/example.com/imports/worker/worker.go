@@ -0,0 +1,13 @@
+package worker // want package:"example.com/imports/workflows -> example.com/imports/internal/db -> database/sql, example.com/imports/workflows -> example.com/imports/internal/db, example.com/imports/workflows -> os/exec"
+
+import (
+	"example.com/imports/clean"
+	"example.com/imports/workflows"
+	"go.temporal.io/sdk/worker"
+)
+
+func Register(w worker.Worker) {
+	w.RegisterWorkflow(clean.CleanWorkflow)
+	w.RegisterWorkflow(workflows.OrderWorkflow) // want "workflow package example.com/imports/workflows imports forbidden package database/sql via example.com/imports/workflows -> example.com/imports/internal/db -> database/sql" "workflow package example.com/imports/workflows imports forbidden package example.com/imports/internal/db$" "workflow package example.com/imports/workflows imports forbidden package os/exec$"
+	w.RegisterWorkflow(workflows.OrderWorkflow)
+}
@@ -0,0 +1,21 @@
+package workflows // want package:"example.com/imports/internal/db -> database/sql, example.com/imports/internal/db, os/exec"
+
+import (
+	"os/exec"
+
+	"example.com/imports/internal/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+func OrderWorkflow(ctx workflow.Context) error {
+	return nil
+}
+
+func RunCommand() error {
+	return exec.Command("true").Run()
+}
+
+func OpenDB() error {
+	_, err := db.Open()
+	return err
+}
@@ -0,0 +1,39 @@
+package library
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+func OrderWorkflow(ctx workflow.Context, orderID string) (string, error) { // want OrderWorkflow:"calls non-determistic function time.Now" "example.com/library.OrderWorkflow is non-deterministic, reason: calls non-determistic function time.Now"
+	return time.Now().String(), nil
+}
+
+func RefundWorkflow(ctx workflow.Context) error {
+	return nil
+}
+
+// Not exported, so not considered a workflow
+func orderHelper(ctx workflow.Context) error { // want orderHelper:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
+
+// Does not return an error, so not considered a workflow
+func OrderTime(ctx workflow.Context) time.Time { // want OrderTime:"calls non-determistic function time.Now"
+	return time.Now()
+}
+
+// Does not accept a context first, so not considered a workflow
+func OrderTimeWithoutContext() error { // want OrderTimeWithoutContext:"calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
+
+type Workflows struct{}
+
+func (Workflows) ShipWorkflow(ctx workflow.Context) error { // want ShipWorkflow:"calls non-determistic function time.Now" "\\(example.com/library.Workflows\\).ShipWorkflow is non-deterministic, reason: calls non-determistic function time.Now"
+	time.Now()
+	return nil
+}
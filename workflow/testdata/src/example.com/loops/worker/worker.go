@@ -0,0 +1,28 @@
+package worker
+
+import (
+	"example.com/loops/workflows"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) {
+	w.RegisterWorkflow(workflows.Forever)     // want `example.com/loops/workflows.Forever may exceed history limits, reason: loop without a static bound calls go.temporal.io/sdk/workflow.ExecuteActivity without continuing as new or checking history length at .*workflows.go:\d+:\d+`
+	w.RegisterWorkflow(workflows.UntilSignal) // want `UntilSignal may exceed history limits`
+	w.RegisterWorkflow(workflows.Bounded)
+	w.RegisterWorkflow(workflows.ContinuesAsNew)
+	w.RegisterWorkflow(workflows.ChecksHistory)
+	w.RegisterWorkflow(workflows.Nested)             // want `Nested may exceed history limits`
+	w.RegisterWorkflow(workflows.ContinuesElsewhere) // want `ContinuesElsewhere may exceed history limits`
+	w.RegisterWorkflow(workflows.ContinuesViaHelper)
+	w.RegisterWorkflow(workflows.CommandViaHelper)        // want `CommandViaHelper may exceed history limits`
+	w.RegisterWorkflow(workflows.VarBound)                // want `VarBound may exceed history limits`
+	w.RegisterWorkflow(workflows.Goroutine)               // want `Goroutine may exceed history limits`
+	w.RegisterWorkflow(func(ctx workflow.Context) error { // want `func literal may exceed history limits, reason: loop without a static bound calls go.temporal.io/sdk/workflow.NewTimer`
+		selector := workflow.NewSelector(ctx)
+		for {
+			workflow.NewTimer(ctx, 0)
+			selector.Select(ctx)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+func Forever(ctx workflow.Context) error { // want Forever:`loop without a static bound calls go.temporal.io/sdk/workflow.ExecuteActivity without continuing as new or checking history length`
+	for {
+		workflow.ExecuteActivity(ctx, Activity)
+		workflow.Sleep(ctx, time.Hour)
+	}
+}
+
+func UntilSignal(ctx workflow.Context) error { // want UntilSignal:`loop without a static bound calls \(go.temporal.io/sdk/workflow.ReceiveChannel\).Receive`
+	ch := workflow.GetSignalChannel(ctx, "done")
+	done := false
+	for !done {
+		ch.Receive(ctx, &done)
+	}
+	return nil
+}
+
+func Bounded(ctx workflow.Context, items []string) error {
+	for i := 0; i < 10; i++ {
+		workflow.ExecuteActivity(ctx, Activity)
+	}
+	for i := 0; i < len(items); i++ {
+		workflow.ExecuteActivity(ctx, Activity)
+	}
+	for range items {
+		workflow.ExecuteActivity(ctx, Activity)
+	}
+	// Loops without commands are fine
+	count := 0
+	for count != 3 {
+		count++
+	}
+	return nil
+}
+
+func ContinuesAsNew(ctx workflow.Context, iteration int) error {
+	for {
+		workflow.Sleep(ctx, time.Hour)
+		iteration++
+		if iteration%1000 == 0 {
+			return workflow.NewContinueAsNewError(ctx, ContinuesAsNew, iteration)
+		}
+	}
+}
+
+func ChecksHistory(ctx workflow.Context) error {
+	for workflow.GetInfo(ctx).GetCurrentHistoryLength() < 10000 {
+		workflow.ExecuteActivity(ctx, Activity)
+	}
+	return nil
+}
+
+func Nested(ctx workflow.Context) error { // want Nested:`loop without a static bound calls go.temporal.io/sdk/workflow.ExecuteActivity`
+	for {
+		for i := 0; i < 10; i++ {
+			workflow.ExecuteActivity(ctx, Activity)
+		}
+	}
+}
+
+func ContinuesElsewhere(ctx workflow.Context, iteration int) error { // want ContinuesElsewhere:`loop without a static bound calls go.temporal.io/sdk/workflow.Sleep without`
+	if iteration > 1000 {
+		return workflow.NewContinueAsNewError(ctx, ContinuesElsewhere, 0)
+	}
+	// The continue-as-new above does not end this loop
+	for {
+		workflow.Sleep(ctx, time.Hour)
+	}
+}
+
+func ContinuesViaHelper(ctx workflow.Context, iteration int) error {
+	for {
+		workflow.Sleep(ctx, time.Hour)
+		iteration++
+		if err := maybeContinueAsNew(ctx, iteration); err != nil {
+			return err
+		}
+	}
+}
+
+func maybeContinueAsNew(ctx workflow.Context, iteration int) error {
+	if workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+		return workflow.NewContinueAsNewError(ctx, ContinuesViaHelper, iteration)
+	}
+	return nil
+}
+
+func CommandViaHelper(ctx workflow.Context) error { // want CommandViaHelper:`loop without a static bound calls go.temporal.io/sdk/workflow.ExecuteActivity via example.com/loops/workflows.poll without`
+	for {
+		if err := poll(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func poll(ctx workflow.Context) error {
+	return workflow.ExecuteActivity(ctx, Activity).Get(ctx, nil)
+}
+
+func VarBound(ctx workflow.Context, n int) error { // want VarBound:`loop without a static bound calls go.temporal.io/sdk/workflow.ExecuteActivity without`
+	// Bounds not assigned in the loop are static
+	for i := 0; i < n; i++ {
+		workflow.ExecuteActivity(ctx, Activity)
+	}
+	for i := 0; i < n; i++ {
+		workflow.ExecuteActivity(ctx, Activity)
+		n++
+	}
+	return nil
+}
+
+func Goroutine(ctx workflow.Context) error { // want Goroutine:`loop without a static bound calls go.temporal.io/sdk/workflow.Sleep without`
+	// Loops in coroutines are also workflow code
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			workflow.Sleep(ctx, time.Minute)
+		}
+	})
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for i := 0; i < 10; i++ {
+			workflow.Sleep(ctx, time.Minute)
+		}
+	})
+	return workflow.ExecuteActivity(ctx, Activity).Get(ctx, nil)
+}
+
+func Activity() error { return nil }
@@ -0,0 +1,16 @@
+package dynamic // want package:"registered activity Charge, dynamic workflow"
+
+import (
+	"example.com/names/workflows"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) {
+	// Any workflow name is accepted by the dynamic workflow and executions in
+	// workflows not registered here are not checked
+	w.RegisterActivity(workflows.Charge)
+	w.RegisterDynamicWorkflow(Dynamic, workflow.DynamicRegisterOptions{})
+}
+
+func Dynamic(ctx workflow.Context, args interface{}) error { return nil }
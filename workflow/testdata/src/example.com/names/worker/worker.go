@@ -0,0 +1,27 @@
+package worker // want package:"registered activity Charge, activity refund, workflow Local, workflow Order"
+
+import (
+	"example.com/names/workflows"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) {
+	w.RegisterWorkflow(workflows.Order) // want `activity "Missing" executed at .*workflows.go:\d+:\d+ is not registered` `workflow "Charge" executed at .*workflows.go:\d+:\d+ is not registered` `activity "Cancel" executed at .*workflows.go:\d+:\d+ is not registered`
+	w.RegisterActivity(workflows.Charge)
+	w.RegisterActivityWithOptions(workflows.Refund, activity.RegisterOptions{Name: workflows.RefundName})
+	w.RegisterWorkflow(Local)
+}
+
+func Local(ctx workflow.Context) error { // want Local:"activity Charge, activity Chrage"
+	workflow.ExecuteActivity(ctx, "Charge")
+	workflow.ExecuteActivity(ctx, "Chrage") // want `activity "Chrage" is not registered`
+	return nil
+}
+
+// Executions in workflows never registered are not checked
+func Unregistered(ctx workflow.Context) error { // want Unregistered:"activity Unknown"
+	workflow.ExecuteActivity(ctx, "Unknown")
+	return nil
+}
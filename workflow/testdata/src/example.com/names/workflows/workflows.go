@@ -0,0 +1,29 @@
+package workflows
+
+import "go.temporal.io/sdk/workflow"
+
+const RefundName = "refund"
+
+func Order(ctx workflow.Context) error { // want Order:"activity Charge, activity refund, activity Missing, workflow Charge, activity Legacy, activity Cancel"
+	// Registered by default name and by explicit name
+	workflow.ExecuteActivity(ctx, "Charge")
+	workflow.ExecuteActivity(ctx, RefundName)
+	// Not registered anywhere
+	workflow.ExecuteActivity(ctx, "Missing")
+	// Registered as an activity but not as a workflow
+	workflow.ExecuteChildWorkflow(ctx, "Charge")
+	// Registered by another process
+	workflow.ExecuteLocalActivity(ctx, "Legacy")
+	// Not executed by name
+	workflow.ExecuteActivity(ctx, Charge)
+	// Executed by a function called by the workflow
+	return cancel(ctx)
+}
+
+func cancel(ctx workflow.Context) error { // want cancel:"activity Cancel"
+	return workflow.ExecuteActivity(ctx, "Cancel").Get(ctx, nil)
+}
+
+func Charge() error { return nil }
+
+func Refund() error { return nil }
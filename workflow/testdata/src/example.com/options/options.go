@@ -0,0 +1,99 @@
+package options
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) {
+	w.RegisterWorkflow(NoOptions)
+	w.RegisterWorkflow(ReassignedOptions)
+	w.RegisterWorkflow(MissingTimeouts)
+	w.RegisterWorkflow(FieldTimeouts)
+	w.RegisterWorkflow(ConditionalOptions)
+	w.RegisterWorkflow(TimeoutFuncs)
+	w.RegisterWorkflow(LocalActivities)
+	w.RegisterWorkflow(ChildWorkflows)
+	w.RegisterWorkflow(UnknownOptions)
+}
+
+func NoOptions(ctx workflow.Context) error {
+	workflow.ExecuteActivity(ctx, Activity) // want "activity Activity executed without activity options on its context"
+	// Other context functions keep the options of the parent
+	workflow.ExecuteActivity(workflow.WithTaskQueue(ctx, "other"), Activity) // want "activity Activity executed without activity options on its context"
+	return nil
+}
+
+func ReassignedOptions(ctx workflow.Context) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+	workflow.ExecuteActivity(ctx, Activity)
+	return nil
+}
+
+func MissingTimeouts(ctx workflow.Context) error {
+	ao := workflow.ActivityOptions{TaskQueue: "other", StartToCloseTimeout: 0}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+	workflow.ExecuteActivity(ctx, Activity) // want "activity Activity executed with activity options that set neither StartToCloseTimeout nor ScheduleToCloseTimeout"
+	negCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{ScheduleToCloseTimeout: -time.Second})
+	workflow.ExecuteActivity(negCtx, "Activity") // want `activity "Activity" executed with activity options that set a negative ScheduleToCloseTimeout`
+	return nil
+}
+
+func FieldTimeouts(ctx workflow.Context) error {
+	var ao workflow.ActivityOptions
+	ao.ScheduleToCloseTimeout = time.Hour
+	workflow.ExecuteActivity(workflow.WithActivityOptions(ctx, ao), Activity)
+	var empty workflow.ActivityOptions
+	workflow.ExecuteActivity(workflow.WithActivityOptions(ctx, empty), Activity) // want "activity Activity executed with activity options that set neither StartToCloseTimeout nor ScheduleToCloseTimeout"
+	return nil
+}
+
+func ConditionalOptions(ctx workflow.Context, fast bool) error {
+	if fast {
+		ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Second})
+	}
+	workflow.ExecuteActivity(ctx, Activity) // want "activity Activity executed without activity options on its context"
+	return nil
+}
+
+func TimeoutFuncs(ctx workflow.Context) error {
+	workflow.ExecuteActivity(workflow.WithStartToCloseTimeout(ctx, time.Minute), Activity)
+	// Activity timeouts do not apply to local activities
+	workflow.ExecuteLocalActivity(workflow.WithStartToCloseTimeout(ctx, time.Minute), Activity) // want "local activity Activity executed without local activity options on its context"
+	return nil
+}
+
+func LocalActivities(ctx workflow.Context) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+	workflow.ExecuteLocalActivity(ctx, Activity) // want "local activity Activity executed without local activity options on its context"
+	ctx = workflow.WithLocalActivityOptions(ctx, workflow.LocalActivityOptions{StartToCloseTimeout: time.Minute})
+	workflow.ExecuteLocalActivity(ctx, Activity)
+	return nil
+}
+
+func ChildWorkflows(ctx workflow.Context) error {
+	// Child workflows do not require options
+	workflow.ExecuteChildWorkflow(ctx, NoOptions)
+	ctx = workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{WorkflowRunTimeout: -time.Minute})
+	workflow.ExecuteChildWorkflow(ctx, NoOptions) // want "child workflow NoOptions executed with child workflow options that set a negative WorkflowRunTimeout"
+	return nil
+}
+
+func UnknownOptions(ctx workflow.Context) error {
+	workflow.ExecuteActivity(workflow.WithActivityOptions(ctx, defaultOptions()), Activity)
+	execute(ctx)
+	return nil
+}
+
+func defaultOptions() workflow.ActivityOptions {
+	return workflow.ActivityOptions{StartToCloseTimeout: time.Minute}
+}
+
+// Contexts given to other functions are assumed to have options
+func execute(ctx workflow.Context) {
+	workflow.ExecuteActivity(ctx, Activity)
+}
+
+func Activity() error { return nil }
@@ -0,0 +1,85 @@
+package payloads
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Worker) {
+	w.RegisterWorkflow(OrderWorkflow) // want `workflow example.com/payloads.OrderWorkflow param order has unserializable type at order.Callback: func\(\) is a function` `workflow example.com/payloads.OrderWorkflow param order has unserializable type at order.Items\[\].Updates: chan int is a channel` `workflow example.com/payloads.OrderWorkflow param order has unserializable type at order.Lookup: map\[Key\]string has unsupported map key type Key` `workflow example.com/payloads.OrderWorkflow result has unserializable type at result: Secret has only unexported fields`
+	w.RegisterWorkflow(ValidWorkflow)
+	w.RegisterActivity(AnyActivity)   // want `activity example.com/payloads.AnyActivity param arg1 has unserializable type at arg1: interface{} is an interface without a concrete type`
+	w.RegisterActivity(&Activities{}) // want `activity \(\*example.com/payloads.Activities\).Complex result has unserializable type at result: complex128 is a complex number`
+}
+
+type Order struct {
+	ID       string
+	Items    []*Item
+	Callback func()
+	Lookup   map[Key]string
+	ByCount  map[int]string
+	Created  time.Time
+	Ignored  func() `json:"-"`
+	Tree     *Order
+	internal chan int
+}
+
+type Item struct {
+	Name    string
+	Updates chan int
+}
+
+type Key struct{ A, B string }
+
+type Secret struct {
+	value string
+}
+
+// Serializes itself so unexported fields are fine
+type Token struct {
+	value string
+}
+
+func (t Token) MarshalJSON() ([]byte, error) { return []byte(`"` + t.value + `"`), nil }
+
+type Empty struct{}
+
+func OrderWorkflow(ctx workflow.Context, order *Order) (Secret, error) {
+	return Secret{}, nil
+}
+
+func ValidWorkflow(ctx workflow.Context, token Token, empty Empty, names map[string][]int) (Token, error) {
+	return token, nil
+}
+
+func AnyActivity(ctx context.Context, _ interface{}) error { return nil }
+
+type Activities struct{}
+
+func (*Activities) Complex() (complex128, error) { return 0, nil }
+
+func (*Activities) Valid(ctx context.Context, name string) (string, error) { return name, nil }
+
+func SignalsAndQueries(ctx workflow.Context) error {
+	ch := workflow.GetSignalChannel(ctx, "signal")
+	var value func()
+	ch.Receive(ctx, &value) // want `signal value has unserializable type at value: func\(\) is a function`
+	var valid string
+	ch.Receive(ctx, &valid)
+	// Non-signal channels are not checked
+	var local workflow.ReceiveChannel
+	local.Receive(ctx, &value)
+	workflow.SignalExternalWorkflow(ctx, "id", "", "signal", make(chan int)) // want `signal value has unserializable type at value: chan int is a channel`
+	workflow.SetQueryHandler(ctx, "query", func(key Key) (func(), error) {   // want `query handler func literal result has unserializable type at result: func\(\) is a function`
+		return nil, nil
+	})
+	return nil
+}
+
+func SignalFromClient(c client.Client) {
+	c.SignalWorkflow(context.Background(), "id", "", "signal", Secret{}) // want `signal value has unserializable type at value: Secret has only unexported fields`
+}
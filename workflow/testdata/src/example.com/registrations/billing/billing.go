@@ -0,0 +1,15 @@
+package billing
+
+import (
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Registry) { // want Register:"param 0 workflow Process \\(example.com/registrations/billing.Process\\), param 0 activity Charge \\(example.com/registrations/billing.Charge\\)"
+	w.RegisterWorkflow(Process)
+	w.RegisterActivity(Charge)
+}
+
+func Process(ctx workflow.Context) error { return nil }
+
+func Charge() error { return nil }
@@ -0,0 +1,30 @@
+package shipping
+
+import (
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Register(w worker.Registry) { // want Register:"param 0 workflow Process \\(example.com/registrations/shipping.Process\\), param 0 activity Ship \\(\\(\\*example.com/registrations/shipping.Activities\\).Ship\\), param 0 activity Track \\(\\(\\*example.com/registrations/shipping.Activities\\).Track\\)"
+	register(w)
+	w.RegisterActivity(&Activities{})
+}
+
+func register(w worker.Registry) { // want register:"param 0 workflow Process"
+	w.RegisterWorkflow(Process)
+}
+
+func RegisterPrefixed(w worker.Registry) { // want RegisterPrefixed:"param 0 activity Shipping_Ship"
+	w.RegisterActivityWithOptions(&Activities{}, activity.RegisterOptions{Name: "Shipping_"})
+}
+
+func Process(ctx workflow.Context) error { return nil }
+
+type Activities struct{}
+
+func (*Activities) Ship() error { return nil }
+
+func (*Activities) Track() error { return nil }
+
+func (*Activities) unexported() {}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"example.com/registrations/billing"
+	"example.com/registrations/shipping"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Conflicts() {
+	var w worker.Worker
+	billing.Register(w)
+	shipping.Register(w)                                                             // want `workflow type "Process" registered on worker w by example.com/registrations/shipping.Process conflicts with example.com/registrations/billing.Process`
+	w.RegisterWorkflowWithOptions(Charge, workflow.RegisterOptions{Name: "Process"}) // want `workflow type "Process" registered on worker w by example.com/registrations/worker.Charge conflicts with example.com/registrations/billing.Process`
+	w.RegisterActivity(Charge)                                                       // want `activity type "Charge" registered on worker w by example.com/registrations/worker.Charge conflicts with example.com/registrations/billing.Charge`
+	w.RegisterActivity(Ship)                                                         // want `activity type "Ship" registered on worker w by example.com/registrations/worker.Ship conflicts with \(\*example.com/registrations/shipping.Activities\).Ship`
+	// Different kinds have different names
+	w.RegisterWorkflow(Ship)
+}
+
+func Duplicates() {
+	var w worker.Worker
+	w.RegisterWorkflow(Charge)
+	w.RegisterWorkflow(Charge) // want `workflow example.com/registrations/worker.Charge registered more than once on worker w as type "Charge"`
+	// Explicitly allowed duplicates
+	w.RegisterActivity(Charge)
+	w.RegisterActivityWithOptions(Charge, activity.RegisterOptions{DisableAlreadyRegisteredCheck: true})
+	// The check is only disabled for the registration setting it
+	w.RegisterActivityWithOptions(Ship, activity.RegisterOptions{DisableAlreadyRegisteredCheck: true})
+	w.RegisterActivity(Ship) // want `activity example.com/registrations/worker.Ship registered more than once on worker w as type "Ship"`
+	for _, wf := range []func(workflow.Context) error{Process, Process} {
+		w.RegisterWorkflow(wf) // want `workflow example.com/registrations/worker.Process registered more than once on worker w as type "Process"`
+	}
+}
+
+func SeparateWorkers() {
+	var w1, w2 worker.Worker
+	billing.Register(w1)
+	shipping.Register(w2)
+	shipping.RegisterPrefixed(w2)
+}
+
+func Charge(ctx workflow.Context) error { return nil }
+
+func Process(ctx workflow.Context) error { return nil }
+
+func Ship() error { return nil }
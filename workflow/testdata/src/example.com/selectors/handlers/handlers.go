@@ -0,0 +1,12 @@
+package handlers
+
+import "go.temporal.io/sdk/workflow"
+
+func Drain(c workflow.ReceiveChannel, more bool) { // want Drain:"param 0 consumed"
+	for c.ReceiveAsync(nil) {
+	}
+}
+
+func Log(c workflow.ReceiveChannel, more bool) { // want Log:"param 0 not consumed"
+	_ = c.Len()
+}
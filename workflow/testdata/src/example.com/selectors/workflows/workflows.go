@@ -0,0 +1,51 @@
+package workflows
+
+import (
+	"example.com/selectors/handlers"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Literals(ctx workflow.Context) error {
+	ch := workflow.GetSignalChannel(ctx, "signal")
+	selector := workflow.NewSelector(ctx)
+	selector.AddReceive(ch, func(c workflow.ReceiveChannel, more bool) {
+		var v string
+		c.Receive(ctx, &v)
+	})
+	// Receiving from the outer channel var is also consuming
+	selector.AddReceive(ch, func(workflow.ReceiveChannel, bool) {
+		ch.ReceiveAsync(nil)
+	})
+	selector.AddReceive(ch, func(c workflow.ReceiveChannel, more bool) { // want "AddReceive callback func literal never receives from channel ch"
+		workflow.GetLogger(ctx).Info("signal pending", "count", c.Len())
+	})
+	selector.AddReceive(ch, func(c workflow.ReceiveChannel, more bool) { // want "AddReceive callback func literal never receives from channel ch"
+		handlers.Log(c, more)
+	})
+	selector.AddReceive(ch, func(c workflow.ReceiveChannel, more bool) {
+		handle(ctx, c)
+	})
+	selector.Select(ctx)
+	return nil
+}
+
+func Named(ctx workflow.Context) error {
+	ch := workflow.GetSignalChannel(ctx, "signal")
+	selector := workflow.NewSelector(ctx)
+	selector.AddReceive(ch, handlers.Drain)
+	selector.AddReceive(ch, handlers.Log) // want "AddReceive callback example.com/selectors/handlers.Log never receives from channel ch"
+	selector.AddReceive(ch, ignore)       // want "AddReceive callback example.com/selectors/workflows.ignore never receives from channel ch"
+	// Callbacks in vars are not checked
+	callback := ignore
+	selector.AddReceive(ch, callback)
+	selector.Select(ctx)
+	return nil
+}
+
+func handle(ctx workflow.Context, c workflow.ReceiveChannel) { // want handle:"param 1 consumed"
+	var v string
+	c.Receive(ctx, &v)
+}
+
+func ignore(c workflow.ReceiveChannel, more bool) { // want ignore:"param 0 not consumed"
+}
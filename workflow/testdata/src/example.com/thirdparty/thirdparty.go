@@ -0,0 +1,7 @@
+package thirdparty
+
+import "time"
+
+func Now() time.Time {
+	return time.Now()
+}
@@ -1,3 +1,6 @@
 package activity
 
-type RegisterOptions struct{}
+type RegisterOptions struct {
+	Name                          string
+	DisableAlreadyRegisteredCheck bool
+}
@@ -0,0 +1,5 @@
+package converter
+
+import "go.temporal.io/sdk/internal"
+
+type Payload = internal.Payload
@@ -0,0 +1,59 @@
+package interceptor
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+type ExecuteWorkflowInput struct{ Args []interface{} }
+
+type WorkflowInboundInterceptor interface {
+	Init(outbound WorkflowOutboundInterceptor) error
+	ExecuteWorkflow(ctx workflow.Context, in *ExecuteWorkflowInput) (interface{}, error)
+	HandleSignal(ctx workflow.Context, signalName string, arg interface{}) error
+	mustEmbedWorkflowInboundInterceptorBase()
+}
+
+type WorkflowOutboundInterceptor interface {
+	ExecuteActivity(ctx workflow.Context, activityType string, args ...interface{}) workflow.Future
+	Now(ctx workflow.Context) time.Time
+	Sleep(ctx workflow.Context, d time.Duration) error
+	mustEmbedWorkflowOutboundInterceptorBase()
+}
+
+type WorkflowInboundInterceptorBase struct {
+	Next WorkflowInboundInterceptor
+}
+
+func (w *WorkflowInboundInterceptorBase) Init(outbound WorkflowOutboundInterceptor) error {
+	return w.Next.Init(outbound)
+}
+
+func (w *WorkflowInboundInterceptorBase) ExecuteWorkflow(ctx workflow.Context, in *ExecuteWorkflowInput) (interface{}, error) {
+	return w.Next.ExecuteWorkflow(ctx, in)
+}
+
+func (w *WorkflowInboundInterceptorBase) HandleSignal(ctx workflow.Context, signalName string, arg interface{}) error {
+	return w.Next.HandleSignal(ctx, signalName, arg)
+}
+
+func (*WorkflowInboundInterceptorBase) mustEmbedWorkflowInboundInterceptorBase() {}
+
+type WorkflowOutboundInterceptorBase struct {
+	Next WorkflowOutboundInterceptor
+}
+
+func (w *WorkflowOutboundInterceptorBase) ExecuteActivity(ctx workflow.Context, activityType string, args ...interface{}) workflow.Future {
+	return w.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+func (w *WorkflowOutboundInterceptorBase) Now(ctx workflow.Context) time.Time {
+	return w.Next.Now(ctx)
+}
+
+func (w *WorkflowOutboundInterceptorBase) Sleep(ctx workflow.Context, d time.Duration) error {
+	return w.Next.Sleep(ctx, d)
+}
+
+func (*WorkflowOutboundInterceptorBase) mustEmbedWorkflowOutboundInterceptorBase() {}
@@ -0,0 +1,8 @@
+package internal
+
+import "net/http"
+
+// Like the real SDK, which reaches net/http through gRPC
+var _ http.Header
+
+type Payload struct{}
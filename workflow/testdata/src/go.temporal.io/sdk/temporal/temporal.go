@@ -0,0 +1,5 @@
+package temporal
+
+func IsCanceledError(err error) bool {
+	return false
+}
@@ -0,0 +1,5 @@
+package testsuite
+
+type TestWorkflowEnvironment struct{}
+
+func (e *TestWorkflowEnvironment) ExecuteWorkflow(workflowFn interface{}, args ...interface{}) {}
@@ -17,6 +17,7 @@ type Registry interface {
 type WorkflowRegistry interface {
 	RegisterWorkflow(w interface{})
 	RegisterWorkflowWithOptions(w interface{}, options workflow.RegisterOptions)
+	RegisterDynamicWorkflow(w interface{}, options workflow.DynamicRegisterOptions)
 }
 
 type ActivityRegistry interface {
@@ -1,5 +1,157 @@
 package workflow
 
-type RegisterOptions struct{}
+import "time"
 
-type Context interface{}
+type RegisterOptions struct {
+	Name                          string
+	DisableAlreadyRegisteredCheck bool
+}
+
+type DynamicRegisterOptions struct{}
+
+type Context interface {
+	Err() error
+}
+
+type CancelFunc func()
+
+func NewDisconnectedContext(parent Context) (ctx Context, cancel CancelFunc) {
+	return parent, func() {}
+}
+
+type Future interface {
+	Get(ctx Context, valuePtr interface{}) error
+	IsReady() bool
+}
+
+type ChildWorkflowFuture interface {
+	Future
+}
+
+type ChildWorkflowOptions struct {
+	WorkflowID               string
+	TaskQueue                string
+	WorkflowExecutionTimeout time.Duration
+	WorkflowRunTimeout       time.Duration
+	WorkflowTaskTimeout      time.Duration
+}
+
+func WithChildOptions(ctx Context, cwo ChildWorkflowOptions) Context {
+	return ctx
+}
+
+func ExecuteChildWorkflow(ctx Context, childWorkflow interface{}, args ...interface{}) ChildWorkflowFuture {
+	return nil
+}
+
+type ReceiveChannel interface {
+	Receive(ctx Context, valuePtr interface{}) (more bool)
+	ReceiveAsync(valuePtr interface{}) (ok bool)
+	Len() int
+}
+
+func GetSignalChannel(ctx Context, signalName string) ReceiveChannel {
+	return nil
+}
+
+func SignalExternalWorkflow(ctx Context, workflowID, runID, signalName string, arg interface{}) Future {
+	return nil
+}
+
+func SetQueryHandler(ctx Context, queryType string, handler interface{}) error {
+	return nil
+}
+
+type ActivityOptions struct {
+	TaskQueue              string
+	ScheduleToCloseTimeout time.Duration
+	ScheduleToStartTimeout time.Duration
+	StartToCloseTimeout    time.Duration
+	HeartbeatTimeout       time.Duration
+}
+
+func WithActivityOptions(ctx Context, options ActivityOptions) Context {
+	return ctx
+}
+
+func WithStartToCloseTimeout(ctx Context, d time.Duration) Context {
+	return ctx
+}
+
+func WithScheduleToCloseTimeout(ctx Context, d time.Duration) Context {
+	return ctx
+}
+
+func WithTaskQueue(ctx Context, name string) Context {
+	return ctx
+}
+
+func ExecuteActivity(ctx Context, activity interface{}, args ...interface{}) Future {
+	return nil
+}
+
+type LocalActivityOptions struct {
+	ScheduleToCloseTimeout time.Duration
+	StartToCloseTimeout    time.Duration
+}
+
+func WithLocalActivityOptions(ctx Context, options LocalActivityOptions) Context {
+	return ctx
+}
+
+func ExecuteLocalActivity(ctx Context, activity interface{}, args ...interface{}) Future {
+	return nil
+}
+
+type Logger interface {
+	Info(msg string, keyvals ...interface{})
+}
+
+func GetLogger(ctx Context) Logger {
+	return nil
+}
+
+func Now(ctx Context) time.Time {
+	return time.Time{}
+}
+
+func Sleep(ctx Context, d time.Duration) error {
+	return nil
+}
+
+func NewTimer(ctx Context, d time.Duration) Future {
+	return nil
+}
+
+type Selector interface {
+	AddReceive(c ReceiveChannel, f func(c ReceiveChannel, more bool)) Selector
+	AddFuture(future Future, f func(f Future)) Selector
+	AddDefault(f func())
+	Select(ctx Context)
+}
+
+func NewSelector(ctx Context) Selector {
+	return nil
+}
+
+func Go(ctx Context, f func(ctx Context)) {}
+
+func NewContinueAsNewError(ctx Context, wfn interface{}, args ...interface{}) error {
+	return nil
+}
+
+type Info struct {
+	WorkflowType string
+}
+
+func (i *Info) GetCurrentHistoryLength() int {
+	return 0
+}
+
+func (i *Info) GetContinueAsNewSuggested() bool {
+	return false
+}
+
+func GetInfo(ctx Context) *Info {
+	return nil
+}
@@ -3,6 +3,7 @@ package workflow_test
 import (
 	"testing"
 
+	"github.com/cretz/temporal-determinist/determinism"
 	"github.com/cretz/temporal-determinist/workflow"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
@@ -17,3 +18,173 @@ func Test(t *testing.T) {
 		"a",
 	)
 }
+
+func TestImports(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewImportChecker(workflow.ImportConfig{
+			DefaultPolicy: workflow.DefaultImportPolicy.Clone().SetAllStrings([]string{"example.com/imports/internal/db/..."}),
+		}).NewAnalyzer(),
+		"example.com/imports/worker",
+	)
+}
+
+func TestCrossPackageRegistrations(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewChecker(workflow.Config{}).NewAnalyzer(),
+		"example.com/bulk/workflows",
+		"example.com/bulk/worker",
+	)
+}
+
+func TestMinConfidence(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewChecker(workflow.Config{
+			MainModule:    "example.com/confidence",
+			MinConfidence: determinism.ConfidenceHigh,
+		}).NewAnalyzer(),
+		"example.com/confidence",
+	)
+}
+
+func TestDiscoverBySignature(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewChecker(workflow.Config{DiscoverBySignature: true}).NewAnalyzer(),
+		"example.com/library",
+	)
+}
+
+func TestRegistrations(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewRegistrationChecker(workflow.RegistrationConfig{}).NewAnalyzer(),
+		"example.com/registrations/billing",
+		"example.com/registrations/shipping",
+		"example.com/registrations/worker",
+	)
+}
+
+func TestPayloads(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewPayloadChecker(workflow.PayloadConfig{}).NewAnalyzer(),
+		"example.com/payloads",
+	)
+}
+
+func TestArguments(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewArgumentChecker(workflow.ArgumentConfig{}).NewAnalyzer(),
+		"example.com/arguments",
+	)
+}
+
+func TestActivityCalls(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewChecker(workflow.Config{MainModule: "example.com/activitycalls", CheckActivityCalls: true}).NewAnalyzer(),
+		"example.com/activitycalls/activities",
+		"example.com/activitycalls/worker",
+	)
+}
+
+func TestActivities(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewActivityChecker(workflow.ActivityConfig{}).NewAnalyzer(),
+		"example.com/activities/helpers",
+		"example.com/activities/worker",
+	)
+}
+
+func TestActivityState(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewActivityStateChecker(workflow.ActivityStateConfig{}).NewAnalyzer(),
+		"example.com/activitystate/activities",
+		"example.com/activitystate/worker",
+	)
+}
+
+func TestNames(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewNameChecker(workflow.NameConfig{ExternalNames: []string{"Legacy"}}).NewAnalyzer(),
+		"example.com/names/workflows",
+		"example.com/names/worker",
+		"example.com/names/dynamic",
+	)
+}
+
+func TestOptions(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewOptionChecker(workflow.OptionConfig{}).NewAnalyzer(),
+		"example.com/options",
+	)
+}
+
+func TestFutures(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewFutureChecker(workflow.FutureConfig{}).NewAnalyzer(),
+		"example.com/futures",
+	)
+}
+
+func TestSelectors(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewSelectorChecker(workflow.SelectorConfig{}).NewAnalyzer(),
+		"example.com/selectors/handlers",
+		"example.com/selectors/workflows",
+	)
+}
+
+func TestContinueAsNew(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewContinueAsNewChecker(workflow.ContinueAsNewConfig{}).NewAnalyzer(),
+		"example.com/continueasnew/workflows",
+		"example.com/continueasnew/worker",
+	)
+}
+
+func TestLoops(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewLoopChecker(workflow.LoopConfig{}).NewAnalyzer(),
+		"example.com/loops/workflows",
+		"example.com/loops/worker",
+	)
+}
+
+func TestCleanup(t *testing.T) {
+	analysistest.Run(
+		t,
+		analysistest.TestData(),
+		workflow.NewCleanupChecker(workflow.CleanupConfig{}).NewAnalyzer(),
+		"example.com/cleanup/workflows",
+		"example.com/cleanup/worker",
+	)
+}